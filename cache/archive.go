@@ -0,0 +1,257 @@
+package cache
+
+import (
+	"archive/tar"
+	"bufio"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/DAtek/gotils"
+)
+
+const (
+	ErrorNotIterable          = gotils.Error("NOT_ITERABLE")
+	ErrorUnsupportedFormat    = gotils.Error("UNSUPPORTED_FORMAT")
+	ErrorInvalidArchiveRecord = gotils.Error("INVALID_ARCHIVE_RECORD")
+)
+
+type ArchiveFormat string
+
+const (
+	ArchiveJsonl ArchiveFormat = "jsonl"
+	ArchiveTar   ArchiveFormat = "tar"
+)
+
+type ArchiveOptions struct {
+	Format  ArchiveFormat
+	Pattern *regexp.Regexp
+}
+
+func (o *ArchiveOptions) validate() {
+	if o.Format == "" {
+		o.Format = ArchiveJsonl
+	}
+}
+
+func (o *ArchiveOptions) matches(key string) bool {
+	return o.Pattern == nil || o.Pattern.MatchString(key)
+}
+
+type archiveRecord struct {
+	Key      string    `json:"key"`
+	Metadata *Metadata `json:"metadata,omitempty"`
+	Body     string    `json:"body,omitempty"`
+	Encoding string    `json:"encoding,omitempty"`
+}
+
+func ExportCache(c ICache, w io.Writer, options ArchiveOptions) (int, error) {
+	options.validate()
+	iterableCache, ok := c.(IIterableCache)
+	if !ok {
+		return 0, ErrorNotIterable
+	}
+
+	keys, err := iterableCache.Keys()
+	if err != nil {
+		return 0, err
+	}
+	sort.Strings(keys)
+
+	var writeRecord func(record *archiveRecord, body string) error
+	var close func() error
+
+	switch options.Format {
+	case ArchiveJsonl:
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		writeRecord = func(record *archiveRecord, body string) error {
+			record.Body, record.Encoding = encodeArchiveBody(body)
+			return encoder.Encode(record)
+		}
+		close = func() error { return nil }
+	case ArchiveTar:
+		tarWriter := tar.NewWriter(w)
+		writeRecord = func(record *archiveRecord, body string) error {
+			return writeTarRecord(tarWriter, record, body)
+		}
+		close = tarWriter.Close
+	default:
+		return 0, ErrorUnsupportedFormat
+	}
+
+	exported := 0
+	for _, key := range keys {
+		if !options.matches(key) {
+			continue
+		}
+
+		body, err := c.Get(key)
+		if err != nil {
+			return exported, err
+		}
+
+		record := &archiveRecord{Key: key}
+		if meta, err := getMetadata(c, key); err == nil {
+			record.Metadata = meta
+		}
+
+		if err := writeRecord(record, body); err != nil {
+			return exported, err
+		}
+
+		exported++
+	}
+
+	return exported, close()
+}
+
+func ImportCache(c ICache, r io.Reader, options ArchiveOptions) (int, error) {
+	options.validate()
+	var readRecord func() (*archiveRecord, string, error)
+
+	switch options.Format {
+	case ArchiveJsonl:
+		reader := bufio.NewReader(r)
+		readRecord = func() (*archiveRecord, string, error) {
+			return readJsonlRecord(reader)
+		}
+	case ArchiveTar:
+		tarReader := tar.NewReader(r)
+		readRecord = func() (*archiveRecord, string, error) {
+			return readTarRecord(tarReader)
+		}
+	default:
+		return 0, ErrorUnsupportedFormat
+	}
+
+	imported := 0
+	for {
+		record, body, err := readRecord()
+		if err == io.EOF {
+			return imported, nil
+		}
+
+		if err != nil {
+			return imported, err
+		}
+
+		if !options.matches(record.Key) {
+			continue
+		}
+
+		if err := c.Set(record.Key, body); err != nil {
+			return imported, err
+		}
+
+		if record.Metadata != nil {
+			if err := setMetadata(c, record.Key, record.Metadata); err != nil {
+				return imported, err
+			}
+		}
+
+		imported++
+	}
+}
+
+func readJsonlRecord(reader *bufio.Reader) (*archiveRecord, string, error) {
+	for {
+		line, err := reader.ReadBytes('\n')
+		if len(strings.TrimSpace(string(line))) == 0 {
+			if err != nil {
+				return nil, "", err
+			}
+			continue
+		}
+
+		record := &archiveRecord{}
+		if err := json.Unmarshal(line, record); err != nil || record.Key == "" {
+			return nil, "", ErrorInvalidArchiveRecord
+		}
+
+		body, decodeErr := decodeArchiveBody(record.Body, record.Encoding)
+		return record, body, decodeErr
+	}
+}
+
+func writeTarRecord(tarWriter *tar.Writer, record *archiveRecord, body string) error {
+	hash := sha256.Sum256([]byte(record.Key))
+	name := hex.EncodeToString(hash[:])
+	header, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range []struct{ name, content string }{{name + ".json", string(header)}, {name + ".body", body}} {
+		err := tarWriter.WriteHeader(&tar.Header{
+			Name:    file.name,
+			Mode:    0644,
+			Size:    int64(len(file.content)),
+			ModTime: time.Unix(0, 0),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		if _, err := io.WriteString(tarWriter, file.content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func readTarRecord(tarReader *tar.Reader) (*archiveRecord, string, error) {
+	header, err := tarReader.Next()
+	if err != nil {
+		return nil, "", err
+	}
+
+	if !strings.HasSuffix(header.Name, ".json") {
+		return nil, "", ErrorInvalidArchiveRecord
+	}
+
+	record := &archiveRecord{}
+	if err := json.NewDecoder(tarReader).Decode(record); err != nil || record.Key == "" {
+		return nil, "", ErrorInvalidArchiveRecord
+	}
+
+	bodyHeader, err := tarReader.Next()
+	if err != nil || bodyHeader.Name != strings.TrimSuffix(header.Name, ".json")+".body" {
+		return nil, "", ErrorInvalidArchiveRecord
+	}
+
+	body, err := io.ReadAll(tarReader)
+	return record, string(body), err
+}
+
+func encodeArchiveBody(body string) (string, string) {
+	if utf8.ValidString(body) {
+		return body, ""
+	}
+
+	return base64.StdEncoding.EncodeToString([]byte(body)), "base64"
+}
+
+func decodeArchiveBody(body, encoding string) (string, error) {
+	switch encoding {
+	case "":
+		return body, nil
+	case "base64":
+		decoded, err := base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return "", ErrorInvalidArchiveRecord
+		}
+		return string(decoded), nil
+	}
+
+	return "", ErrorInvalidArchiveRecord
+}
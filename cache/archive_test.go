@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"bytes"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchive(t *testing.T) {
+	for _, format := range []ArchiveFormat{ArchiveJsonl, ArchiveTar} {
+		t.Run("Exports and imports entries with metadata as "+string(format), func(t *testing.T) {
+			src := newArchiveSource()
+			fetchedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+			meta := &Metadata{FetchedAt: fetchedAt, StatusCode: 200, ETag: `"v1"`, Header: http.Header{"Content-Type": {"text/html"}}}
+			gotils.NilOrPanic(src.SetMetadata("http://demo.example/beers", meta))
+			buf := &bytes.Buffer{}
+
+			exported, exportErr := ExportCache(src, buf, ArchiveOptions{Format: format})
+			dst := NewMemoryCache(0, 0).(IMetadataCache)
+			imported, importErr := ImportCache(dst, buf, ArchiveOptions{Format: format})
+
+			assert.Nil(t, exportErr)
+			assert.Nil(t, importErr)
+			assert.Equal(t, 3, exported)
+			assert.Equal(t, 3, imported)
+			assert.Equal(t, "<h1>Beers</h1>", gotils.ResultOrPanic(dst.Get("http://demo.example/beers")))
+			assert.Equal(t, "\xff\xfe binary", gotils.ResultOrPanic(dst.Get("http://demo.example/logo.png")))
+			assert.Equal(t, "{}", gotils.ResultOrPanic(dst.Get("POST http://other.example/api")))
+			assert.Equal(t, meta, gotils.ResultOrPanic(dst.GetMetadata("http://demo.example/beers")))
+		})
+	}
+
+	t.Run("Exports matching keys only", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+
+		exported, err := ExportCache(newArchiveSource(), buf, ArchiveOptions{Pattern: regexp.MustCompile(`^http://demo\.example/`)})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, exported)
+		assert.Equal(t, 2, strings.Count(buf.String(), "\n"))
+		assert.NotContains(t, buf.String(), "other.example")
+	})
+
+	t.Run("Imports matching keys only", func(t *testing.T) {
+		buf := &bytes.Buffer{}
+		ExportCache(newArchiveSource(), buf, ArchiveOptions{})
+		dst := NewMemoryCache(0, 0)
+
+		imported, err := ImportCache(dst, buf, ArchiveOptions{Pattern: regexp.MustCompile(`beers$`)})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, imported)
+		assert.True(t, dst.Has("http://demo.example/beers"))
+		assert.False(t, dst.Has("http://demo.example/logo.png"))
+	})
+
+	t.Run("Imports into file cache", func(t *testing.T) {
+		defer deleteCacheDir()
+		buf := &bytes.Buffer{}
+		ExportCache(newArchiveSource(), buf, ArchiveOptions{Format: ArchiveTar})
+		dst := newCache().(IIterableCache)
+
+		imported, err := ImportCache(dst, buf, ArchiveOptions{Format: ArchiveTar})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 3, imported)
+		assert.ElementsMatch(t, []string{"http://demo.example/beers", "http://demo.example/logo.png", "POST http://other.example/api"}, gotils.ResultOrPanic(dst.Keys()))
+	})
+
+	t.Run("Returns error if cache is not iterable", func(t *testing.T) {
+		_, err := ExportCache(&MockCache{}, &bytes.Buffer{}, ArchiveOptions{})
+		_, wrappedErr := ExportCache(NewInstrumentedCache(&MockCache{}), &bytes.Buffer{}, ArchiveOptions{})
+
+		assert.Equal(t, ErrorNotIterable, err)
+		assert.Equal(t, ErrorNotIterable, wrappedErr)
+	})
+
+	t.Run("Returns error if format is not supported", func(t *testing.T) {
+		_, exportErr := ExportCache(newArchiveSource(), &bytes.Buffer{}, ArchiveOptions{Format: "zip"})
+		_, importErr := ImportCache(NewMemoryCache(0, 0), &bytes.Buffer{}, ArchiveOptions{Format: "zip"})
+
+		assert.Equal(t, ErrorUnsupportedFormat, exportErr)
+		assert.Equal(t, ErrorUnsupportedFormat, importErr)
+	})
+
+	t.Run("Returns error if record is invalid", func(t *testing.T) {
+		for _, line := range []string{"beer\n", `{"body":"lager"}` + "\n", `{"key":"beer","body":"!","encoding":"base64"}` + "\n"} {
+			_, err := ImportCache(NewMemoryCache(0, 0), strings.NewReader(line), ArchiveOptions{})
+
+			assert.Equal(t, ErrorInvalidArchiveRecord, err)
+		}
+	})
+
+	t.Run("Imports empty archive", func(t *testing.T) {
+		imported, err := ImportCache(NewMemoryCache(0, 0), strings.NewReader("\n"), ArchiveOptions{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, imported)
+	})
+}
+
+func newArchiveSource() IMetadataCache {
+	c := NewMemoryCache(0, 0).(IMetadataCache)
+	gotils.NilOrPanic(c.Set("http://demo.example/beers", "<h1>Beers</h1>"))
+	gotils.NilOrPanic(c.Set("http://demo.example/logo.png", "\xff\xfe binary"))
+	gotils.NilOrPanic(c.Set("POST http://other.example/api", "{}"))
+	return c
+}
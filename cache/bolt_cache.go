@@ -0,0 +1,247 @@
+package cache
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+const boltCompactTxSize = 64 << 20
+
+var (
+	entriesBucket  = []byte("entries")
+	metadataBucket = []byte("metadata")
+)
+
+type IBoltCache interface {
+	IMetadataCache
+	IIterableCache
+	SetMany(values map[string]string) error
+	Close() error
+}
+
+type boltCache struct {
+	db          *bolt.DB
+	compression *compression
+}
+
+func NewBoltCache(path string, options ...CompressionOption) (IBoltCache, error) {
+	db, err := openBoltDb(path, false)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{entriesBucket, metadataBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &boltCache{db: db, compression: newCompression(options)}, nil
+}
+
+func (c *boltCache) Get(key string) (string, error) {
+	var data []byte
+	err := c.db.View(func(tx *bolt.Tx) error {
+		data = copyBytes(tx.Bucket(entriesBucket).Get([]byte(key)))
+		return nil
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	if data == nil {
+		return "", ErrorKeyNotFound
+	}
+
+	return decodeValue(data)
+}
+
+func (c *boltCache) Set(key string, val string) error {
+	data, err := c.compression.encodeValue(key, val)
+	if err != nil {
+		return err
+	}
+
+	metaData, err := json.Marshal(&Metadata{FetchedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		return putEntry(tx, key, data, metaData)
+	})
+}
+
+func (c *boltCache) SetMany(values map[string]string) error {
+	entries := map[string][]byte{}
+	for key, val := range values {
+		data, err := c.compression.encodeValue(key, val)
+		if err != nil {
+			return err
+		}
+		entries[key] = data
+	}
+
+	metaData, err := json.Marshal(&Metadata{FetchedAt: time.Now()})
+	if err != nil {
+		return err
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		for key, data := range entries {
+			if err := putEntry(tx, key, data, metaData); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (c *boltCache) Delete(key string) error {
+	return c.db.Update(func(tx *bolt.Tx) error {
+		entries := tx.Bucket(entriesBucket)
+		if entries.Get([]byte(key)) == nil {
+			return ErrorKeyNotFound
+		}
+
+		if err := tx.Bucket(metadataBucket).Delete([]byte(key)); err != nil {
+			return err
+		}
+
+		return entries.Delete([]byte(key))
+	})
+}
+
+func (c *boltCache) Has(key string) bool {
+	found := false
+	c.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(entriesBucket).Get([]byte(key))
+		if data == nil {
+			return nil
+		}
+
+		_, _, err := decodeEntry(data)
+		found = err == nil
+		return nil
+	})
+
+	return found
+}
+
+func (c *boltCache) GetMetadata(key string) (*Metadata, error) {
+	var data []byte
+	err := c.db.View(func(tx *bolt.Tx) error {
+		data = copyBytes(tx.Bucket(metadataBucket).Get([]byte(key)))
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if data == nil {
+		return nil, ErrorKeyNotFound
+	}
+
+	meta := &Metadata{}
+	if err := json.Unmarshal(data, meta); err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+func (c *boltCache) SetMetadata(key string, meta *Metadata) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	return c.db.Update(func(tx *bolt.Tx) error {
+		if tx.Bucket(entriesBucket).Get([]byte(key)) == nil {
+			return ErrorKeyNotFound
+		}
+
+		return tx.Bucket(metadataBucket).Put([]byte(key), data)
+	})
+}
+
+func (c *boltCache) Keys() ([]string, error) {
+	keys := []string{}
+	err := c.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(entriesBucket).ForEach(func(key, _ []byte) error {
+			keys = append(keys, string(key))
+			return nil
+		})
+	})
+
+	return keys, err
+}
+
+func (c *boltCache) Close() error {
+	return c.db.Close()
+}
+
+func CompactBoltCache(path string) error {
+	src, err := openBoltDb(path, true)
+	if err != nil {
+		return err
+	}
+
+	defer src.Close()
+	compactPath := path + ".compact"
+	if err := os.Remove(compactPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	dst, err := openBoltDb(compactPath, false)
+	if err != nil {
+		return err
+	}
+
+	if err := bolt.Compact(dst, src, boltCompactTxSize); err != nil {
+		dst.Close()
+		os.Remove(compactPath)
+		return err
+	}
+
+	if err := dst.Close(); err != nil {
+		os.Remove(compactPath)
+		return err
+	}
+
+	return os.Rename(compactPath, path)
+}
+
+func openBoltDb(path string, readOnly bool) (*bolt.DB, error) {
+	return bolt.Open(path, 0644, &bolt.Options{Timeout: 1 * time.Second, ReadOnly: readOnly})
+}
+
+func putEntry(tx *bolt.Tx, key string, data, metaData []byte) error {
+	if err := tx.Bucket(entriesBucket).Put([]byte(key), data); err != nil {
+		return err
+	}
+
+	return tx.Bucket(metadataBucket).Put([]byte(key), metaData)
+}
+
+func copyBytes(data []byte) []byte {
+	if data == nil {
+		return nil
+	}
+
+	return append([]byte{}, data...)
+}
@@ -0,0 +1,270 @@
+package cache
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/DAtek/gotils"
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+	bolt "go.etcd.io/bbolt"
+)
+
+var boltPath = path.Join(tmpDir, "cache.db")
+
+func newBoltCache(options ...CompressionOption) IBoltCache {
+	return gotils.ResultOrPanic(NewBoltCache(boltPath, options...))
+}
+
+func deleteBoltCache() {
+	gotils.NilOrPanic(os.RemoveAll(boltPath))
+}
+
+func TestBoltCache(t *testing.T) {
+	t.Run("Returns stored value", func(t *testing.T) {
+		defer deleteBoltCache()
+		c := newBoltCache()
+		defer c.Close()
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		val, err := c.Get("beer")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "lager", val)
+		assert.True(t, c.Has("beer"))
+	})
+
+	t.Run("Returns error if key not cached", func(t *testing.T) {
+		defer deleteBoltCache()
+		c := newBoltCache()
+		defer c.Close()
+
+		_, err := c.Get("beer")
+
+		assert.Equal(t, ErrorKeyNotFound, err)
+		assert.False(t, c.Has("beer"))
+	})
+
+	t.Run("Deletes value", func(t *testing.T) {
+		defer deleteBoltCache()
+		c := newBoltCache()
+		defer c.Close()
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		assert.Nil(t, c.Delete("beer"))
+		assert.False(t, c.Has("beer"))
+		assert.Equal(t, ErrorKeyNotFound, c.Delete("beer"))
+		_, err := c.GetMetadata("beer")
+		assert.Equal(t, ErrorKeyNotFound, err)
+	})
+
+	t.Run("Keeps values after reopening", func(t *testing.T) {
+		defer deleteBoltCache()
+		c := newBoltCache(WithCodec(CodecGzip, 1))
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(c.Close())
+
+		c = newBoltCache()
+		defer c.Close()
+		val, err := c.Get("beer")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "lager", val)
+	})
+
+	t.Run("Compresses values", func(t *testing.T) {
+		defer deleteBoltCache()
+		c := newBoltCache(WithCodec(CodecBrotli, brotli.BestSpeed))
+		defer c.Close()
+		val := strings.Repeat("lager", 1000)
+		gotils.NilOrPanic(c.Set("beer", val))
+
+		data := []byte{}
+		gotils.NilOrPanic(c.(*boltCache).db.View(func(tx *bolt.Tx) error {
+			data = tx.Bucket(entriesBucket).Get([]byte("beer"))
+			return nil
+		}))
+
+		assert.Less(t, len(data), len(val))
+		assert.Equal(t, val, gotils.ResultOrPanic(c.Get("beer")))
+	})
+
+	t.Run("Treats corrupt entry as missing", func(t *testing.T) {
+		defer deleteBoltCache()
+		c := newBoltCache()
+		defer c.Close()
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(c.(*boltCache).db.Update(func(tx *bolt.Tx) error {
+			data := tx.Bucket(entriesBucket).Get([]byte("beer"))
+			return tx.Bucket(entriesBucket).Put([]byte("beer"), data[:len(data)-1])
+		}))
+
+		_, err := c.Get("beer")
+
+		assert.Equal(t, ErrorCorruptEntry, err)
+		assert.False(t, c.Has("beer"))
+	})
+
+	t.Run("Stores multiple values in one batch", func(t *testing.T) {
+		defer deleteBoltCache()
+		c := newBoltCache()
+		defer c.Close()
+
+		err := c.SetMany(map[string]string{"beer": "lager", "wine": "red"})
+
+		assert.Nil(t, err)
+		assert.Equal(t, "lager", gotils.ResultOrPanic(c.Get("beer")))
+		assert.Equal(t, "red", gotils.ResultOrPanic(c.Get("wine")))
+		assert.False(t, gotils.ResultOrPanic(c.GetMetadata("wine")).FetchedAt.IsZero())
+	})
+
+	t.Run("Returns error if batch can't be encoded", func(t *testing.T) {
+		defer deleteBoltCache()
+		c := newBoltCache(WithCodec("lzma", 0))
+		defer c.Close()
+
+		assert.Equal(t, ErrorUnsupportedCodec, c.SetMany(map[string]string{"beer": "lager"}))
+		assert.Equal(t, ErrorUnsupportedCodec, c.Set("beer", "lager"))
+	})
+
+	t.Run("Stores metadata", func(t *testing.T) {
+		defer deleteBoltCache()
+		c := newBoltCache()
+		defer c.Close()
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		assert.False(t, gotils.ResultOrPanic(c.GetMetadata("beer")).FetchedAt.IsZero())
+		meta := &Metadata{StatusCode: http.StatusOK, ETag: `"v1"`, Header: http.Header{"Etag": {`"v1"`}}}
+
+		assert.Nil(t, c.SetMetadata("beer", meta))
+
+		storedMeta, err := c.GetMetadata("beer")
+		assert.Nil(t, err)
+		assert.Equal(t, meta.ETag, storedMeta.ETag)
+		assert.Equal(t, meta.Header, storedMeta.Header)
+		assert.Equal(t, ErrorKeyNotFound, c.SetMetadata("wine", meta))
+	})
+
+	t.Run("Returns keys", func(t *testing.T) {
+		defer deleteBoltCache()
+		c := newBoltCache()
+		defer c.Close()
+		gotils.NilOrPanic(c.SetMany(map[string]string{"http://demo.example/1": "1", "http://demo.example/2": "2"}))
+
+		keys, err := c.Keys()
+
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"http://demo.example/1", "http://demo.example/2"}, keys)
+	})
+
+	t.Run("Returns error if database can't be opened", func(t *testing.T) {
+		_, err := NewBoltCache("/var/this_directory_does_not_exists/cache.db")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Is safe for concurrent use", func(t *testing.T) {
+		defer deleteBoltCache()
+		c := newBoltCache(WithCodec(CodecNone, 0))
+		defer c.Close()
+		wg := &sync.WaitGroup{}
+
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				key := fmt.Sprint(i)
+				gotils.NilOrPanic(c.Set(key, key))
+				gotils.ResultOrPanic(c.Get(key))
+			}(i)
+		}
+
+		wg.Wait()
+		assert.Equal(t, 20, len(gotils.ResultOrPanic(c.Keys())))
+	})
+}
+
+func TestCompactBoltCache(t *testing.T) {
+	t.Run("Shrinks database after deleting entries", func(t *testing.T) {
+		defer deleteBoltCache()
+		c := newBoltCache(WithCodec(CodecNone, 0))
+		values := map[string]string{}
+		for i := 0; i < 200; i++ {
+			values[strconv.Itoa(i)] = strings.Repeat(strconv.Itoa(i), 1000)
+		}
+		gotils.NilOrPanic(c.SetMany(values))
+		for i := 1; i < 200; i++ {
+			gotils.NilOrPanic(c.Delete(strconv.Itoa(i)))
+		}
+		gotils.NilOrPanic(c.Close())
+		sizeBefore := gotils.ResultOrPanic(os.Stat(boltPath)).Size()
+
+		err := CompactBoltCache(boltPath)
+
+		assert.Nil(t, err)
+		assert.Less(t, gotils.ResultOrPanic(os.Stat(boltPath)).Size(), sizeBefore)
+		c = newBoltCache()
+		defer c.Close()
+		assert.Equal(t, values["0"], gotils.ResultOrPanic(c.Get("0")))
+		assert.Equal(t, []string{"0"}, gotils.ResultOrPanic(c.Keys()))
+	})
+
+	t.Run("Replaces file left by a failed compaction", func(t *testing.T) {
+		defer deleteBoltCache()
+		c := newBoltCache()
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(c.Close())
+		gotils.NilOrPanic(os.WriteFile(boltPath+".compact", []byte("partial"), 0644))
+
+		err := CompactBoltCache(boltPath)
+
+		assert.Nil(t, err)
+		c = newBoltCache()
+		defer c.Close()
+		assert.Equal(t, "lager", gotils.ResultOrPanic(c.Get("beer")))
+	})
+
+	t.Run("Returns error if database not exists", func(t *testing.T) {
+		err := CompactBoltCache("/var/this_directory_does_not_exists/cache.db")
+
+		assert.Error(t, err)
+	})
+}
+
+func BenchmarkBoltCache(b *testing.B) {
+	page := strings.Repeat("<div class=\"article\"><p>Lorem ipsum dolor sit amet</p></div>\n", 200)
+
+	b.Run("set", func(b *testing.B) {
+		defer deleteBoltCache()
+		c := newBoltCache(WithCodec(CodecZstd, 3))
+		defer c.Close()
+		b.SetBytes(int64(len(page)))
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			gotils.NilOrPanic(c.Set(strconv.Itoa(i%1000), page))
+		}
+	})
+
+	b.Run("set-many", func(b *testing.B) {
+		defer deleteBoltCache()
+		c := newBoltCache(WithCodec(CodecZstd, 3))
+		defer c.Close()
+		b.SetBytes(int64(len(page)))
+		b.ResetTimer()
+
+		values := map[string]string{}
+		for i := 0; i < b.N; i++ {
+			values[strconv.Itoa(i%1000)] = page
+			if len(values) == 100 || i == b.N-1 {
+				gotils.NilOrPanic(c.SetMany(values))
+				values = map[string]string{}
+			}
+		}
+	})
+}
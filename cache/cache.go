@@ -1,8 +1,35 @@
 package cache
 
+import (
+	"net/http"
+	"time"
+)
+
 type ICache interface {
 	Get(key string) (string, error)
 	Set(key string, val string) error
 	Delete(key string) error
 	Has(key string) bool
 }
+
+type Metadata struct {
+	FetchedAt    time.Time   `json:"fetchedAt"`
+	StatusCode   int         `json:"statusCode,omitempty"`
+	Header       http.Header `json:"header,omitempty"`
+	ETag         string      `json:"etag,omitempty"`
+	LastModified string      `json:"lastModified,omitempty"`
+	Method       string      `json:"method,omitempty"`
+	Url          string      `json:"url,omitempty"`
+	RequestBody  string      `json:"requestBody,omitempty"`
+}
+
+type IMetadataCache interface {
+	ICache
+	GetMetadata(key string) (*Metadata, error)
+	SetMetadata(key string, meta *Metadata) error
+}
+
+type IIterableCache interface {
+	ICache
+	Keys() ([]string, error)
+}
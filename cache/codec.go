@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"sync"
+
+	"github.com/DAtek/gotils"
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+const ErrorUnsupportedCodec = gotils.Error("UNSUPPORTED_CODEC")
+
+type Codec string
+
+const (
+	CodecBrotli Codec = "brotli"
+	CodecGzip   Codec = "gzip"
+	CodecZstd   Codec = "zstd"
+	CodecNone   Codec = "none"
+)
+
+type CompressionOption func(*compression)
+
+func WithCodec(codec Codec, level int) CompressionOption {
+	return func(c *compression) {
+		c.codec = codec
+		c.level = level
+	}
+}
+
+type compression struct {
+	codec Codec
+	level int
+}
+
+func newCompression(options []CompressionOption) *compression {
+	c := &compression{codec: CodecBrotli, level: brotli.BestCompression}
+	for _, option := range options {
+		option(c)
+	}
+
+	return c
+}
+
+func (c *compression) encodeValue(key, val string) ([]byte, error) {
+	compressed, err := compress(c.codec, c.level, []byte(val))
+	if err != nil {
+		return nil, err
+	}
+
+	return encodeEntry(&fileHeader{Key: key, Codec: c.codec}, compressed)
+}
+
+func decodeValue(data []byte) (string, error) {
+	header, payload, err := decodeEntry(data)
+	if err != nil {
+		return "", err
+	}
+
+	codec := CodecBrotli
+	if header != nil && header.Codec != "" {
+		codec = header.Codec
+	}
+
+	decompressed, err := decompress(codec, payload)
+	if err != nil {
+		return "", err
+	}
+
+	return string(decompressed), nil
+}
+
+var zstdDecoder, _ = zstd.NewReader(nil)
+
+var zstdEncoders = sync.Map{}
+
+func compress(codec Codec, level int, data []byte) ([]byte, error) {
+	switch codec {
+	case CodecBrotli:
+		buf := &bytes.Buffer{}
+		return writeCompressed(buf, brotli.NewWriterLevel(buf, level), data)
+	case CodecGzip:
+		buf := &bytes.Buffer{}
+		writer, err := gzip.NewWriterLevel(buf, level)
+		if err != nil {
+			return nil, err
+		}
+		return writeCompressed(buf, writer, data)
+	case CodecZstd:
+		encoder, err := getZstdEncoder(level)
+		if err != nil {
+			return nil, err
+		}
+		return encoder.EncodeAll(data, nil), nil
+	case CodecNone:
+		return data, nil
+	}
+
+	return nil, ErrorUnsupportedCodec
+}
+
+func decompress(codec Codec, data []byte) ([]byte, error) {
+	switch codec {
+	case CodecBrotli:
+		return io.ReadAll(brotli.NewReader(bytes.NewReader(data)))
+	case CodecGzip:
+		reader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer reader.Close()
+		return io.ReadAll(reader)
+	case CodecZstd:
+		return zstdDecoder.DecodeAll(data, nil)
+	case CodecNone:
+		return data, nil
+	}
+
+	return nil, ErrorUnsupportedCodec
+}
+
+func writeCompressed(buf *bytes.Buffer, writer io.WriteCloser, data []byte) ([]byte, error) {
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func getZstdEncoder(level int) (*zstd.Encoder, error) {
+	encoderLevel := zstd.EncoderLevelFromZstd(level)
+	if encoder, ok := zstdEncoders.Load(encoderLevel); ok {
+		return encoder.(*zstd.Encoder), nil
+	}
+
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(encoderLevel))
+	if err != nil {
+		return nil, err
+	}
+
+	actual, _ := zstdEncoders.LoadOrStore(encoderLevel, encoder)
+	return actual.(*zstd.Encoder), nil
+}
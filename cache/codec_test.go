@@ -0,0 +1,62 @@
+package cache
+
+import (
+	"compress/gzip"
+	"strings"
+	"testing"
+
+	"github.com/DAtek/gotils"
+	"github.com/andybalholm/brotli"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCodec(t *testing.T) {
+	data := []byte(strings.Repeat("<p>Lorem ipsum dolor sit amet</p>\n", 50))
+	levels := map[Codec]int{
+		CodecBrotli: brotli.BestSpeed,
+		CodecGzip:   gzip.BestSpeed,
+		CodecZstd:   3,
+		CodecNone:   0,
+	}
+
+	for codec, level := range levels {
+		t.Run("Decompresses compressed data with "+string(codec), func(t *testing.T) {
+			compressed := gotils.ResultOrPanic(compress(codec, level, data))
+
+			decompressed, err := decompress(codec, compressed)
+
+			assert.Nil(t, err)
+			assert.Equal(t, data, decompressed)
+		})
+	}
+
+	t.Run("Compresses data", func(t *testing.T) {
+		for _, codec := range []Codec{CodecBrotli, CodecGzip, CodecZstd} {
+			compressed := gotils.ResultOrPanic(compress(codec, levels[codec], data))
+
+			assert.Less(t, len(compressed), len(data))
+		}
+	})
+
+	t.Run("Returns error for unsupported codec", func(t *testing.T) {
+		_, compressErr := compress("lzma", 0, data)
+		_, decompressErr := decompress("lzma", data)
+
+		assert.Equal(t, ErrorUnsupportedCodec, compressErr)
+		assert.Equal(t, ErrorUnsupportedCodec, decompressErr)
+	})
+
+	t.Run("Returns error for invalid gzip level", func(t *testing.T) {
+		_, err := compress(CodecGzip, 42, data)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Returns error for invalid compressed data", func(t *testing.T) {
+		for _, codec := range []Codec{CodecBrotli, CodecGzip, CodecZstd} {
+			_, err := decompress(codec, []byte("invalid"))
+
+			assert.Error(t, err)
+		}
+	})
+}
@@ -0,0 +1,125 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+)
+
+type IContentAddressedCache interface {
+	IMetadataCache
+	IIterableCache
+	GetHash(key string) (string, error)
+	CollectGarbage() (int, error)
+}
+
+type dedupCache struct {
+	refs   ICache
+	bodies ICache
+	mutex  *sync.RWMutex
+}
+
+func NewDedupCache(refs ICache, bodies ICache) IContentAddressedCache {
+	return &dedupCache{refs: refs, bodies: bodies, mutex: &sync.RWMutex{}}
+}
+
+func (c *dedupCache) Get(key string) (string, error) {
+	hash, err := c.refs.Get(key)
+	if err != nil {
+		return "", err
+	}
+
+	return c.bodies.Get(hash)
+}
+
+func (c *dedupCache) Set(key string, val string) error {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	hash := HashBody(val)
+
+	if !c.bodies.Has(hash) {
+		if err := c.bodies.Set(hash, val); err != nil {
+			return err
+		}
+	}
+
+	return c.refs.Set(key, hash)
+}
+
+func (c *dedupCache) Has(key string) bool {
+	hash, err := c.refs.Get(key)
+	if err != nil {
+		return false
+	}
+
+	return c.bodies.Has(hash)
+}
+
+func (c *dedupCache) Delete(key string) error {
+	return c.refs.Delete(key)
+}
+
+func (c *dedupCache) GetHash(key string) (string, error) {
+	return c.refs.Get(key)
+}
+
+func (c *dedupCache) GetMetadata(key string) (*Metadata, error) {
+	return getMetadata(c.refs, key)
+}
+
+func (c *dedupCache) SetMetadata(key string, meta *Metadata) error {
+	return setMetadata(c.refs, key, meta)
+}
+
+func (c *dedupCache) Keys() ([]string, error) {
+	return getKeys(c.refs)
+}
+
+func (c *dedupCache) CollectGarbage() (int, error) {
+	refs, refsOk := c.refs.(IIterableCache)
+	bodies, bodiesOk := c.bodies.(IIterableCache)
+	if !refsOk || !bodiesOk {
+		return 0, ErrorNotIterable
+	}
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	keys, err := refs.Keys()
+	if err != nil {
+		return 0, err
+	}
+
+	referenced := map[string]struct{}{}
+	for _, key := range keys {
+		hash, err := c.refs.Get(key)
+		if err != nil {
+			continue
+		}
+		referenced[hash] = struct{}{}
+	}
+
+	hashes, err := bodies.Keys()
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, hash := range hashes {
+		if _, ok := referenced[hash]; ok {
+			continue
+		}
+
+		if err := c.bodies.Delete(hash); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+
+	return removed, nil
+}
+
+func HashBody(body string) string {
+	hash := sha256.Sum256([]byte(body))
+	return hex.EncodeToString(hash[:])
+}
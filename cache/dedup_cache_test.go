@@ -0,0 +1,128 @@
+package cache
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedupCache(t *testing.T) {
+	t.Run("Stores identical bodies once", func(t *testing.T) {
+		refs := NewMemoryCache(0, 0)
+		bodies := NewMemoryCache(0, 0).(IIterableCache)
+		c := NewDedupCache(refs, bodies)
+
+		gotils.NilOrPanic(c.Set("http://demo.example/1", "page"))
+		gotils.NilOrPanic(c.Set("http://demo.example/1?print=1", "page"))
+		gotils.NilOrPanic(c.Set("http://demo.example/2", "other page"))
+
+		assert.Equal(t, "page", gotils.ResultOrPanic(c.Get("http://demo.example/1")))
+		assert.Equal(t, "page", gotils.ResultOrPanic(c.Get("http://demo.example/1?print=1")))
+		assert.Equal(t, "other page", gotils.ResultOrPanic(c.Get("http://demo.example/2")))
+		assert.ElementsMatch(t, []string{HashBody("page"), HashBody("other page")}, gotils.ResultOrPanic(bodies.Keys()))
+	})
+
+	t.Run("Returns hash of body", func(t *testing.T) {
+		c := NewDedupCache(NewMemoryCache(0, 0), NewMemoryCache(0, 0))
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		hash, err := c.GetHash("beer")
+
+		assert.Nil(t, err)
+		assert.Equal(t, HashBody("lager"), hash)
+		assert.Equal(t, 64, len(hash))
+	})
+
+	t.Run("Returns error if key not cached", func(t *testing.T) {
+		c := NewDedupCache(NewMemoryCache(0, 0), NewMemoryCache(0, 0))
+
+		_, err := c.Get("beer")
+		_, hashErr := c.GetHash("beer")
+
+		assert.Equal(t, ErrorKeyNotFound, err)
+		assert.Equal(t, ErrorKeyNotFound, hashErr)
+		assert.False(t, c.Has("beer"))
+	})
+
+	t.Run("Reports missing body", func(t *testing.T) {
+		bodies := NewMemoryCache(0, 0)
+		c := NewDedupCache(NewMemoryCache(0, 0), bodies)
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(bodies.Delete(HashBody("lager")))
+
+		assert.False(t, c.Has("beer"))
+	})
+
+	t.Run("Deletes reference only", func(t *testing.T) {
+		c := NewDedupCache(NewMemoryCache(0, 0), NewMemoryCache(0, 0))
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(c.Set("ale", "lager"))
+
+		assert.Nil(t, c.Delete("beer"))
+
+		assert.False(t, c.Has("beer"))
+		assert.Equal(t, "lager", gotils.ResultOrPanic(c.Get("ale")))
+	})
+
+	t.Run("Frees body of deleted and overwritten references", func(t *testing.T) {
+		bodies := NewMemoryCache(0, 0).(IIterableCache)
+		c := NewDedupCache(NewMemoryCache(0, 0), bodies)
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(c.Set("ale", "lager"))
+		gotils.NilOrPanic(c.Set("wine", "red"))
+		gotils.NilOrPanic(c.Set("wine", "white"))
+
+		removed1, err1 := c.CollectGarbage()
+		gotils.NilOrPanic(c.Delete("beer"))
+		removed2, err2 := c.CollectGarbage()
+		gotils.NilOrPanic(c.Delete("ale"))
+		removed3, err3 := c.CollectGarbage()
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Nil(t, err3)
+		assert.Equal(t, 1, removed1)
+		assert.Equal(t, 0, removed2)
+		assert.Equal(t, 1, removed3)
+		assert.Equal(t, []string{HashBody("white")}, gotils.ResultOrPanic(bodies.Keys()))
+	})
+
+	t.Run("Returns error if caches are not iterable", func(t *testing.T) {
+		c := NewDedupCache(NewMemoryCache(0, 0), &MockCache{})
+		nonIterableRefs := NewDedupCache(&MockCache{}, NewMemoryCache(0, 0))
+
+		_, err := c.CollectGarbage()
+		_, keysErr := nonIterableRefs.Keys()
+
+		assert.Equal(t, ErrorNotIterable, err)
+		assert.Equal(t, ErrorNotIterable, keysErr)
+	})
+
+	t.Run("Returns error if storing fails", func(t *testing.T) {
+		err := errors.New("UNEXPECTED_ERROR")
+		failing := &MockCache{
+			Has_: func(key string) bool { return false },
+			Set_: func(key, val string) error { return err },
+		}
+
+		bodyErr := NewDedupCache(NewMemoryCache(0, 0), failing).Set("beer", "lager")
+		refErr := NewDedupCache(failing, NewMemoryCache(0, 0)).Set("beer", "lager")
+
+		assert.Equal(t, err, bodyErr)
+		assert.Equal(t, err, refErr)
+	})
+
+	t.Run("Stores metadata and keys in references", func(t *testing.T) {
+		c := NewDedupCache(NewMemoryCache(0, 0), NewMemoryCache(0, 0))
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		meta := &Metadata{StatusCode: http.StatusOK}
+
+		assert.Nil(t, c.SetMetadata("beer", meta))
+
+		assert.Equal(t, meta, gotils.ResultOrPanic(c.GetMetadata("beer")))
+		assert.Equal(t, []string{"beer"}, gotils.ResultOrPanic(c.Keys()))
+	})
+}
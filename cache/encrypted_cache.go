@@ -0,0 +1,356 @@
+package cache
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+
+	"github.com/DAtek/gotils"
+)
+
+const (
+	ErrorInvalidKeyId      = gotils.Error("INVALID_KEY_ID")
+	ErrorDuplicateKeyId    = gotils.Error("DUPLICATE_KEY_ID")
+	ErrorUnknownKeyId      = gotils.Error("UNKNOWN_KEY_ID")
+	ErrorInvalidCiphertext = gotils.Error("INVALID_CIPHERTEXT")
+)
+
+const encryptedMagic = "GRWE"
+
+type EncryptionKey struct {
+	Id  string
+	Key []byte
+}
+
+type IEncryptedCache interface {
+	IMetadataCache
+	IIterableCache
+	Rotate() (int, error)
+}
+
+type encryptedCache struct {
+	store     ICache
+	currentId string
+	keyIds    []string
+	ciphers   map[string]cipher.AEAD
+	indexKeys map[string][]byte
+}
+
+func NewEncryptedCache(store ICache, current EncryptionKey, previous ...EncryptionKey) (IEncryptedCache, error) {
+	c := &encryptedCache{
+		store:     store,
+		currentId: current.Id,
+		ciphers:   map[string]cipher.AEAD{},
+		indexKeys: map[string][]byte{},
+	}
+
+	for _, key := range append([]EncryptionKey{current}, previous...) {
+		if key.Id == "" || len(key.Id) > 255 {
+			return nil, ErrorInvalidKeyId
+		}
+
+		if _, ok := c.ciphers[key.Id]; ok {
+			return nil, ErrorDuplicateKeyId
+		}
+
+		block, err := aes.NewCipher(key.Key)
+		if err != nil {
+			return nil, err
+		}
+
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		mac := hmac.New(sha256.New, key.Key)
+		mac.Write([]byte("index"))
+		c.keyIds = append(c.keyIds, key.Id)
+		c.ciphers[key.Id] = aead
+		c.indexKeys[key.Id] = mac.Sum(nil)
+	}
+
+	return c, nil
+}
+
+func (c *encryptedCache) Get(key string) (string, error) {
+	storeKey := c.locate(key)
+	data, err := c.store.Get(storeKey)
+	if err != nil {
+		return "", err
+	}
+
+	_, plaintext, err := c.decrypt(storeKey, data)
+	if err != nil {
+		return "", err
+	}
+
+	storedKey, val, err := decodeEncryptedEntry(plaintext)
+	if err != nil || storedKey != key {
+		return "", ErrorInvalidCiphertext
+	}
+
+	return val, nil
+}
+
+func (c *encryptedCache) Set(key string, val string) error {
+	storeKey := c.storeKey(c.currentId, key)
+	data, err := c.encrypt(storeKey, encodeEncryptedEntry(key, val))
+	if err != nil {
+		return err
+	}
+
+	if err := c.store.Set(storeKey, data); err != nil {
+		return err
+	}
+
+	for _, keyId := range c.keyIds[1:] {
+		previousKey := c.storeKey(keyId, key)
+		if !c.store.Has(previousKey) {
+			continue
+		}
+
+		if err := c.store.Delete(previousKey); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (c *encryptedCache) Has(key string) bool {
+	return c.store.Has(c.locate(key))
+}
+
+func (c *encryptedCache) Delete(key string) error {
+	deleted := false
+	for _, keyId := range c.keyIds {
+		storeKey := c.storeKey(keyId, key)
+		if !c.store.Has(storeKey) {
+			continue
+		}
+
+		if err := c.store.Delete(storeKey); err != nil {
+			return err
+		}
+		deleted = true
+	}
+
+	if !deleted {
+		return c.store.Delete(c.storeKey(c.currentId, key))
+	}
+
+	return nil
+}
+
+func (c *encryptedCache) GetMetadata(key string) (*Metadata, error) {
+	meta, err := getMetadata(c.store, c.locate(key))
+	if err != nil {
+		return nil, err
+	}
+
+	decrypted := *meta
+	for _, field := range []*string{&decrypted.Url, &decrypted.RequestBody} {
+		if *field == "" {
+			continue
+		}
+
+		data, err := base64.StdEncoding.DecodeString(*field)
+		if err != nil {
+			return nil, ErrorInvalidCiphertext
+		}
+
+		if _, *field, err = c.decrypt(key, string(data)); err != nil {
+			return nil, err
+		}
+	}
+
+	return &decrypted, nil
+}
+
+func (c *encryptedCache) SetMetadata(key string, meta *Metadata) error {
+	storeKey := c.locate(key)
+	if meta == nil {
+		return setMetadata(c.store, storeKey, meta)
+	}
+
+	stripped := *meta
+	stripped.Header = nil
+	for _, field := range []*string{&stripped.Url, &stripped.RequestBody} {
+		if *field == "" {
+			continue
+		}
+
+		data, err := c.encrypt(key, *field)
+		if err != nil {
+			return err
+		}
+
+		*field = base64.StdEncoding.EncodeToString([]byte(data))
+	}
+
+	return setMetadata(c.store, storeKey, &stripped)
+}
+
+func (c *encryptedCache) Keys() ([]string, error) {
+	keys, _, err := c.entries()
+	return keys, err
+}
+
+func (c *encryptedCache) Rotate() (int, error) {
+	keys, keyIds, err := c.entries()
+	if err != nil {
+		return 0, err
+	}
+
+	rotated := 0
+	for i, key := range keys {
+		if keyIds[i] == c.currentId {
+			continue
+		}
+
+		val, err := c.Get(key)
+		if err != nil {
+			return rotated, err
+		}
+
+		meta, metaErr := c.GetMetadata(key)
+		if err := c.Set(key, val); err != nil {
+			return rotated, err
+		}
+
+		if metaErr == nil {
+			if err := c.SetMetadata(key, meta); err != nil {
+				return rotated, err
+			}
+		}
+
+		rotated++
+	}
+
+	return rotated, nil
+}
+
+func (c *encryptedCache) entries() ([]string, []string, error) {
+	storeKeys, err := getKeys(c.store)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keys := []string{}
+	keyIds := []string{}
+	seen := map[string]bool{}
+	for _, storeKey := range storeKeys {
+		data, err := c.store.Get(storeKey)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		keyId, plaintext, err := c.decrypt(storeKey, data)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key, _, err := decodeEncryptedEntry(plaintext)
+		if err != nil {
+			return nil, nil, ErrorInvalidCiphertext
+		}
+
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+		keys = append(keys, key)
+		keyIds = append(keyIds, keyId)
+	}
+
+	return keys, keyIds, nil
+}
+
+func (c *encryptedCache) locate(key string) string {
+	for _, keyId := range c.keyIds {
+		storeKey := c.storeKey(keyId, key)
+		if c.store.Has(storeKey) {
+			return storeKey
+		}
+	}
+
+	return c.storeKey(c.currentId, key)
+}
+
+func (c *encryptedCache) storeKey(keyId, key string) string {
+	mac := hmac.New(sha256.New, c.indexKeys[keyId])
+	mac.Write([]byte(key))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func (c *encryptedCache) encrypt(additional, val string) (string, error) {
+	aead := c.ciphers[c.currentId]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(encryptedMagic)
+	buf.WriteByte(byte(len(c.currentId)))
+	buf.WriteString(c.currentId)
+	buf.Write(nonce)
+	buf.Write(aead.Seal(nil, nonce, []byte(val), additionalData(c.currentId, additional)))
+	return buf.String(), nil
+}
+
+func (c *encryptedCache) decrypt(additional, data string) (string, string, error) {
+	if len(data) < len(encryptedMagic)+1 || data[:len(encryptedMagic)] != encryptedMagic {
+		return "", "", ErrorInvalidCiphertext
+	}
+
+	data = data[len(encryptedMagic):]
+	idLength := int(data[0])
+	if len(data) < 1+idLength {
+		return "", "", ErrorInvalidCiphertext
+	}
+
+	keyId := data[1 : 1+idLength]
+	data = data[1+idLength:]
+	aead, ok := c.ciphers[keyId]
+	if !ok {
+		return "", "", ErrorUnknownKeyId
+	}
+
+	if len(data) < aead.NonceSize() {
+		return "", "", ErrorInvalidCiphertext
+	}
+
+	nonce, ciphertext := []byte(data[:aead.NonceSize()]), []byte(data[aead.NonceSize():])
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData(keyId, additional))
+	if err != nil {
+		return "", "", ErrorInvalidCiphertext
+	}
+
+	return keyId, string(plaintext), nil
+}
+
+func additionalData(keyId, additional string) []byte {
+	return []byte(keyId + "\n" + additional)
+}
+
+func encodeEncryptedEntry(key, val string) string {
+	return string(binary.AppendUvarint(nil, uint64(len(key)))) + key + val
+}
+
+func decodeEncryptedEntry(entry string) (string, string, error) {
+	length, n := binary.Uvarint([]byte(entry))
+	if n <= 0 || uint64(len(entry)-n) < length {
+		return "", "", ErrorInvalidCiphertext
+	}
+
+	return entry[n : n+int(length)], entry[n+int(length):], nil
+}
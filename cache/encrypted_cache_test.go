@@ -0,0 +1,204 @@
+package cache
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncryptedCache(t *testing.T) {
+	oldKey := EncryptionKey{Id: "2024", Key: bytes.Repeat([]byte{1}, 32)}
+	newKey := EncryptionKey{Id: "2025", Key: bytes.Repeat([]byte{2}, 16)}
+
+	newEncryptedCache := func(store ICache, current EncryptionKey, previous ...EncryptionKey) IEncryptedCache {
+		return gotils.ResultOrPanic(NewEncryptedCache(store, current, previous...))
+	}
+
+	storeKey := func(c IEncryptedCache, key string) string {
+		return c.(*encryptedCache).storeKey(c.(*encryptedCache).currentId, key)
+	}
+
+	t.Run("Returns stored value", func(t *testing.T) {
+		store := NewMemoryCache(0, 0)
+		c := newEncryptedCache(store, oldKey)
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		val, err := c.Get("beer")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "lager", val)
+		assert.True(t, c.Has("beer"))
+		assert.False(t, store.Has("beer"))
+		assert.NotContains(t, gotils.ResultOrPanic(store.Get(storeKey(c, "beer"))), "lager")
+	})
+
+	t.Run("Encrypts file cache at rest", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newEncryptedCache(NewFileCache(newCacheDir(), WithCodec(CodecNone, 0)), oldKey)
+		url := "https://example.com/profile?user=alice"
+		gotils.NilOrPanic(c.Set(url, "personal data"))
+		gotils.NilOrPanic(c.SetMetadata(url, &Metadata{Url: url, RequestBody: "password=secret"}))
+
+		contents := ""
+		gotils.NilOrPanic(filepath.Walk(cacheDir, func(path string, info os.FileInfo, err error) error {
+			if !info.IsDir() {
+				contents += string(gotils.ResultOrPanic(os.ReadFile(path)))
+			}
+			return err
+		}))
+
+		assert.NotContains(t, contents, "personal data")
+		assert.NotContains(t, contents, "example.com")
+		assert.NotContains(t, contents, "password=secret")
+		assert.Equal(t, "personal data", gotils.ResultOrPanic(c.Get(url)))
+		assert.Equal(t, url, gotils.ResultOrPanic(c.GetMetadata(url)).Url)
+		assert.Equal(t, "password=secret", gotils.ResultOrPanic(c.GetMetadata(url)).RequestBody)
+		assert.Equal(t, []string{url}, gotils.ResultOrPanic(c.Keys()))
+	})
+
+	t.Run("Uses random nonce", func(t *testing.T) {
+		store := NewMemoryCache(0, 0)
+		c := newEncryptedCache(store, oldKey)
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(c.Set("ale", "lager"))
+
+		assert.NotEqual(t, gotils.ResultOrPanic(store.Get(storeKey(c, "beer")))[6:], gotils.ResultOrPanic(store.Get(storeKey(c, "ale")))[6:])
+	})
+
+	t.Run("Reads values encrypted with previous key", func(t *testing.T) {
+		store := NewMemoryCache(0, 0)
+		gotils.NilOrPanic(newEncryptedCache(store, oldKey).Set("beer", "lager"))
+
+		val, err := newEncryptedCache(store, newKey, oldKey).Get("beer")
+		_, unknownErr := newEncryptedCache(store, newKey).Get("beer")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "lager", val)
+		assert.Equal(t, ErrorKeyNotFound, unknownErr)
+	})
+
+	t.Run("Rotates values to current key", func(t *testing.T) {
+		store := NewMemoryCache(0, 0)
+		gotils.NilOrPanic(newEncryptedCache(store, oldKey).Set("beer", "lager"))
+		c := newEncryptedCache(store, newKey, oldKey)
+		gotils.NilOrPanic(c.Set("wine", "red"))
+		gotils.NilOrPanic(c.SetMetadata("beer", &Metadata{ETag: `"v1"`, Url: "https://example.com/beer"}))
+
+		rotated, err := c.Rotate()
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, rotated)
+		rotatedCache := newEncryptedCache(store, newKey)
+		assert.Equal(t, "lager", gotils.ResultOrPanic(rotatedCache.Get("beer")))
+		assert.Equal(t, "red", gotils.ResultOrPanic(rotatedCache.Get("wine")))
+		assert.Equal(t, `"v1"`, gotils.ResultOrPanic(rotatedCache.GetMetadata("beer")).ETag)
+		assert.Equal(t, "https://example.com/beer", gotils.ResultOrPanic(rotatedCache.GetMetadata("beer")).Url)
+		assert.ElementsMatch(t, []string{"beer", "wine"}, gotils.ResultOrPanic(rotatedCache.Keys()))
+		assert.Len(t, gotils.ResultOrPanic(store.(IIterableCache).Keys()), 2)
+	})
+
+	t.Run("Deletes value written with previous key", func(t *testing.T) {
+		store := NewMemoryCache(0, 0)
+		gotils.NilOrPanic(newEncryptedCache(store, oldKey).Set("beer", "lager"))
+		c := newEncryptedCache(store, newKey, oldKey)
+
+		assert.Nil(t, c.Delete("beer"))
+		assert.False(t, c.Has("beer"))
+		assert.Equal(t, ErrorKeyNotFound, c.Delete("beer"))
+	})
+
+	t.Run("Rotate returns error if value can't be decrypted", func(t *testing.T) {
+		store := NewMemoryCache(0, 0)
+		gotils.NilOrPanic(store.Set("beer", "plain"))
+
+		_, err := newEncryptedCache(store, newKey).Rotate()
+
+		assert.Equal(t, ErrorInvalidCiphertext, err)
+	})
+
+	t.Run("Rotate returns error if store is not iterable", func(t *testing.T) {
+		c := newEncryptedCache(&MockCache{}, newKey)
+
+		rotated, err := c.Rotate()
+
+		assert.Equal(t, ErrorNotIterable, err)
+		assert.Equal(t, 0, rotated)
+	})
+
+	t.Run("Returns error if value was tampered with", func(t *testing.T) {
+		store := NewMemoryCache(0, 0)
+		c := newEncryptedCache(store, oldKey)
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		data := []byte(gotils.ResultOrPanic(store.Get(storeKey(c, "beer"))))
+		data[len(data)-1] ^= 1
+		gotils.NilOrPanic(store.Set(storeKey(c, "beer"), string(data)))
+		gotils.NilOrPanic(c.Set("wine", "red"))
+		gotils.NilOrPanic(store.Set(storeKey(c, "ale"), gotils.ResultOrPanic(store.Get(storeKey(c, "wine")))))
+
+		_, tamperedErr := c.Get("beer")
+		_, movedErr := c.Get("ale")
+
+		assert.Equal(t, ErrorInvalidCiphertext, tamperedErr)
+		assert.Equal(t, ErrorInvalidCiphertext, movedErr)
+	})
+
+	t.Run("Returns error for invalid ciphertext", func(t *testing.T) {
+		store := NewMemoryCache(0, 0)
+		c := newEncryptedCache(store, oldKey)
+
+		for _, data := range []string{"", "plain", encryptedMagic + "\x09", encryptedMagic + "\x042024short"} {
+			gotils.NilOrPanic(store.Set(storeKey(c, "beer"), data))
+
+			_, err := c.Get("beer")
+
+			assert.Equal(t, ErrorInvalidCiphertext, err)
+		}
+	})
+
+	t.Run("Returns error for invalid key", func(t *testing.T) {
+		_, sizeErr := NewEncryptedCache(NewMemoryCache(0, 0), EncryptionKey{Id: "1", Key: []byte("short")})
+		_, idErr := NewEncryptedCache(NewMemoryCache(0, 0), EncryptionKey{Key: oldKey.Key})
+
+		_, duplicateErr := NewEncryptedCache(NewMemoryCache(0, 0), oldKey, EncryptionKey{Id: oldKey.Id, Key: newKey.Key})
+
+		assert.Error(t, sizeErr)
+		assert.Equal(t, ErrorInvalidKeyId, idErr)
+		assert.Equal(t, ErrorDuplicateKeyId, duplicateErr)
+	})
+
+	t.Run("Returns error of the store", func(t *testing.T) {
+		err := errors.New("UNEXPECTED_ERROR")
+		c := newEncryptedCache(&MockCache{
+			Get_:    func(key string) (string, error) { return "", err },
+			Set_:    func(key, val string) error { return err },
+			Delete_: func(key string) error { return err },
+			Has_:    func(key string) bool { return false },
+		}, oldKey)
+
+		_, getErr := c.Get("beer")
+
+		assert.Equal(t, err, getErr)
+		assert.Equal(t, err, c.Set("beer", "lager"))
+		assert.Equal(t, err, c.Delete("beer"))
+	})
+
+	t.Run("Doesn't store response headers in metadata", func(t *testing.T) {
+		c := newEncryptedCache(NewMemoryCache(0, 0), oldKey)
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		meta := &Metadata{StatusCode: http.StatusOK, Header: http.Header{"Set-Cookie": {"session=secret"}}}
+
+		gotils.NilOrPanic(c.SetMetadata("beer", meta))
+
+		storedMeta := gotils.ResultOrPanic(c.GetMetadata("beer"))
+		assert.Nil(t, storedMeta.Header)
+		assert.Equal(t, http.StatusOK, storedMeta.StatusCode)
+		assert.NotNil(t, meta.Header)
+		assert.Equal(t, []string{"beer"}, gotils.ResultOrPanic(c.Keys()))
+	})
+}
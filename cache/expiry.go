@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"regexp"
+	"time"
+)
+
+type TTLRule struct {
+	Pattern *regexp.Regexp
+	TTL     time.Duration
+}
+
+type ExpiryPolicy struct {
+	TTL   time.Duration
+	Rules []TTLRule
+}
+
+func (p *ExpiryPolicy) GetTTL(key string) time.Duration {
+	for _, rule := range p.Rules {
+		if rule.Pattern.MatchString(key) {
+			return rule.TTL
+		}
+	}
+
+	return p.TTL
+}
+
+func (p *ExpiryPolicy) IsExpired(key string, meta *Metadata, now time.Time) bool {
+	if p == nil || meta == nil || meta.FetchedAt.IsZero() {
+		return false
+	}
+
+	ttl := p.GetTTL(key)
+	return ttl > 0 && !now.Before(meta.FetchedAt.Add(ttl))
+}
@@ -0,0 +1,53 @@
+package cache
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpiryPolicy(t *testing.T) {
+	now := time.Now()
+	policy := &ExpiryPolicy{
+		TTL: time.Hour,
+		Rules: []TTLRule{
+			{Pattern: regexp.MustCompile(`/news/`), TTL: time.Minute},
+			{Pattern: regexp.MustCompile(`/archive/`), TTL: 0},
+		},
+	}
+
+	t.Run("Uses global TTL if no rule matches", func(t *testing.T) {
+		assert.Equal(t, time.Hour, policy.GetTTL("http://demo.example/about"))
+	})
+
+	t.Run("Uses TTL of the first matching rule", func(t *testing.T) {
+		assert.Equal(t, time.Minute, policy.GetTTL("http://demo.example/news/1"))
+	})
+
+	t.Run("Entry older than TTL is expired", func(t *testing.T) {
+		meta := &Metadata{FetchedAt: now.Add(-2 * time.Minute)}
+
+		assert.True(t, policy.IsExpired("http://demo.example/news/1", meta, now))
+		assert.False(t, policy.IsExpired("http://demo.example/about", meta, now))
+	})
+
+	t.Run("Entry never expires with zero TTL", func(t *testing.T) {
+		meta := &Metadata{FetchedAt: now.Add(-24 * 365 * time.Hour)}
+
+		assert.False(t, policy.IsExpired("http://demo.example/archive/1", meta, now))
+	})
+
+	t.Run("Entry without fetch time is not expired", func(t *testing.T) {
+		assert.False(t, policy.IsExpired("http://demo.example/news/1", &Metadata{}, now))
+		assert.False(t, policy.IsExpired("http://demo.example/news/1", nil, now))
+	})
+
+	t.Run("Nothing expires without policy", func(t *testing.T) {
+		var nilPolicy *ExpiryPolicy
+		meta := &Metadata{FetchedAt: now.Add(-24 * time.Hour)}
+
+		assert.False(t, nilPolicy.IsExpired("http://demo.example/news/1", meta, now))
+	})
+}
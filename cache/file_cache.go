@@ -1,75 +1,71 @@
 package cache
 
 import (
-	"bytes"
 	"crypto/md5"
 	"encoding/hex"
-	"io"
+	"encoding/json"
+	"errors"
 	"os"
 	"path"
 	"sync"
-
-	"github.com/andybalholm/brotli"
+	"time"
 )
 
+const lockStripes = 256
+
 type fileCache struct {
-	workdir string
-	mutex   *sync.Mutex
+	workdir     string
+	locks       []sync.RWMutex
+	compression *compression
 }
 
-func NewFileCache(workdir string) ICache {
+func NewFileCache(workdir string, options ...CompressionOption) ICache {
 	return &fileCache{
-		workdir: workdir,
-		mutex:   &sync.Mutex{},
+		workdir:     workdir,
+		locks:       make([]sync.RWMutex, lockStripes),
+		compression: newCompression(options),
 	}
 }
 
 func (c *fileCache) Get(key string) (string, error) {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	file, err := os.Open(c.getFilePath(key))
+	lock := c.getLock(key)
+	lock.RLock()
+	defer lock.RUnlock()
+	data, err := os.ReadFile(c.getFilePath(key))
 
 	if err != nil {
 		return "", err
 	}
 
-	defer file.Close()
-	reader := brotli.NewReader(file)
-	decompressedBuf := &bytes.Buffer{}
-	_, copyErr := io.Copy(decompressedBuf, reader)
-
-	if copyErr != nil {
-		return "", copyErr
-	}
-
-	return decompressedBuf.String(), nil
+	return decodeValue(data)
 }
 
 func (c *fileCache) Set(key string, val string) error {
-	c.mutex.Lock()
-	defer c.mutex.Unlock()
-	filePath := c.getFilePath(key)
-	file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY, 0755)
-
+	data, err := c.compression.encodeValue(key, val)
 	if err != nil {
 		return err
 	}
 
-	compressor := brotli.NewWriterLevel(file, brotli.BestCompression)
-	io.WriteString(compressor, val)
+	lock := c.getLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+	filePath := c.getFilePath(key)
 
-	if flushErr := compressor.Flush(); flushErr != nil {
+	if err := createShardDir(filePath); err != nil {
 		return err
 	}
 
-	if closeErr := file.Close(); closeErr != nil {
-		return closeErr
+	if err := writeFileAtomic(filePath, data); err != nil {
+		return err
 	}
 
-	return nil
+	return c.writeMetadata(key, &Metadata{FetchedAt: time.Now()})
 }
 
 func (c *fileCache) Delete(key string) error {
+	lock := c.getLock(key)
+	lock.Lock()
+	defer lock.Unlock()
 	filepath := c.getFilePath(key)
 	_, err := os.Stat(filepath)
 
@@ -77,16 +73,107 @@ func (c *fileCache) Delete(key string) error {
 		return err
 	}
 
+	if err := os.Remove(c.getMetadataPath(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
 	return os.Remove(filepath)
 }
 
 func (c *fileCache) Has(key string) bool {
-	_, err := os.Stat(c.getFilePath(key))
-	return err == nil
+	lock := c.getLock(key)
+	lock.RLock()
+	defer lock.RUnlock()
+	data, err := os.ReadFile(c.getFilePath(key))
+
+	if err != nil {
+		return false
+	}
+
+	return verifyEntry(data) == nil
+}
+
+func (c *fileCache) GetMetadata(key string) (*Metadata, error) {
+	lock := c.getLock(key)
+	lock.RLock()
+	defer lock.RUnlock()
+	data, err := os.ReadFile(c.getMetadataPath(key))
+
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &Metadata{}
+	if err := json.Unmarshal(data, meta); err != nil {
+		return nil, err
+	}
+
+	return meta, nil
+}
+
+func (c *fileCache) SetMetadata(key string, meta *Metadata) error {
+	lock := c.getLock(key)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if _, err := os.Stat(c.getFilePath(key)); err != nil {
+		return err
+	}
+
+	return c.writeMetadata(key, meta)
+}
+
+func (c *fileCache) writeMetadata(key string, meta *Metadata) error {
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(c.getMetadataPath(key), data)
+}
+
+func (c *fileCache) Keys() ([]string, error) {
+	entries, err := ListFileCacheEntries(c.workdir)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{}
+	for _, entry := range entries {
+		if entry.Key != "" {
+			keys = append(keys, entry.Key)
+		}
+	}
+
+	return keys, nil
+}
+
+func (c *fileCache) getLock(key string) *sync.RWMutex {
+	hash := md5.Sum([]byte(key))
+	return &c.locks[int(hash[0])%len(c.locks)]
+}
+
+func (c *fileCache) getMetadataPath(key string) string {
+	return c.getFilePath(key) + metadataSuffix
 }
 
 func (c *fileCache) getFilePath(key string) string {
 	hash := md5.Sum([]byte(key))
-	filename := hex.EncodeToString(hash[:])
-	return path.Join(c.workdir, filename)
+	return getShardedPath(c.workdir, hex.EncodeToString(hash[:]))
+}
+
+func createShardDir(filePath string) error {
+	shardDir := path.Dir(filePath)
+
+	for _, dir := range []string{path.Dir(shardDir), shardDir} {
+		if err := os.Mkdir(dir, 0755); err != nil && !errors.Is(err, os.ErrExist) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getShardedPath(workdir string, filename string) string {
+	return path.Join(workdir, filename[0:2], filename[2:4], filename)
 }
@@ -0,0 +1,149 @@
+package cache
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/DAtek/gotils"
+)
+
+const ErrorCorruptEntry = gotils.Error("CORRUPT_ENTRY")
+
+const fileMagic = "GRWL"
+
+var checksumTable = crc32.MakeTable(crc32.Castagnoli)
+
+type fileHeader struct {
+	Key      string `json:"key"`
+	Checksum uint32 `json:"checksum"`
+	Codec    Codec  `json:"codec,omitempty"`
+}
+
+func encodeEntry(header *fileHeader, payload []byte) ([]byte, error) {
+	header.Checksum = crc32.Checksum(payload, checksumTable)
+	headerData, err := json.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(fileMagic)
+	buf.Write(headerData)
+	buf.WriteByte('\n')
+	buf.Write(payload)
+	return buf.Bytes(), nil
+}
+
+func decodeEntry(data []byte) (*fileHeader, []byte, error) {
+	if len(data) == 0 {
+		return nil, nil, ErrorCorruptEntry
+	}
+
+	if !bytes.HasPrefix(data, []byte(fileMagic)) {
+		return nil, data, nil
+	}
+
+	data = data[len(fileMagic):]
+	end := bytes.IndexByte(data, '\n')
+	if end < 0 {
+		return nil, nil, ErrorCorruptEntry
+	}
+
+	header := &fileHeader{}
+	if err := json.Unmarshal(data[:end], header); err != nil {
+		return nil, nil, ErrorCorruptEntry
+	}
+
+	payload := data[end+1:]
+	if crc32.Checksum(payload, checksumTable) != header.Checksum {
+		return nil, nil, ErrorCorruptEntry
+	}
+
+	return header, payload, nil
+}
+
+func verifyEntry(data []byte) error {
+	header, _, err := decodeEntry(data)
+	if err != nil || header != nil {
+		return err
+	}
+
+	_, err = decodeValue(data)
+	return err
+}
+
+func readEntryHeader(filePath string) (*fileHeader, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+	reader := bufio.NewReader(file)
+	magic := make([]byte, len(fileMagic))
+
+	if _, err := io.ReadFull(reader, magic); err != nil || string(magic) != fileMagic {
+		return nil, nil
+	}
+
+	line, err := reader.ReadBytes('\n')
+	if err != nil {
+		return nil, ErrorCorruptEntry
+	}
+
+	header := &fileHeader{}
+	if err := json.Unmarshal(line, header); err != nil {
+		return nil, ErrorCorruptEntry
+	}
+
+	return header, nil
+}
+
+func writeFileAtomic(filePath string, data []byte) error {
+	dir := filepath.Dir(filePath)
+	file, err := os.CreateTemp(dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+
+	tmpPath := file.Name()
+	defer os.Remove(tmpPath)
+
+	if err := file.Chmod(0644); err != nil {
+		file.Close()
+		return err
+	}
+
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, filePath); err != nil {
+		return err
+	}
+
+	syncDir(dir)
+	return nil
+}
+
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileCacheFormat(t *testing.T) {
+	t.Run("Decodes encoded entry", func(t *testing.T) {
+		payload := []byte("compressed\npayload")
+		data := gotils.ResultOrPanic(encodeEntry(&fileHeader{}, payload))
+
+		header, decodedPayload, err := decodeEntry(data)
+
+		assert.Nil(t, err)
+		assert.NotNil(t, header)
+		assert.Equal(t, payload, decodedPayload)
+	})
+
+	t.Run("Returns whole data of legacy entry", func(t *testing.T) {
+		data := []byte("legacy")
+
+		header, payload, err := decodeEntry(data)
+
+		assert.Nil(t, err)
+		assert.Nil(t, header)
+		assert.Equal(t, data, payload)
+	})
+
+	t.Run("Returns error for empty entry", func(t *testing.T) {
+		_, _, err := decodeEntry([]byte{})
+
+		assert.Equal(t, ErrorCorruptEntry, err)
+	})
+
+	t.Run("Returns error if checksum does not match", func(t *testing.T) {
+		data := gotils.ResultOrPanic(encodeEntry(&fileHeader{}, []byte("payload")))
+
+		_, _, err := decodeEntry(data[:len(data)-1])
+
+		assert.Equal(t, ErrorCorruptEntry, err)
+	})
+
+	t.Run("Returns error if header is truncated", func(t *testing.T) {
+		_, _, err := decodeEntry([]byte(fileMagic + `{"checksum":`))
+
+		assert.Equal(t, ErrorCorruptEntry, err)
+	})
+
+	t.Run("Returns error if header is invalid", func(t *testing.T) {
+		_, _, err := decodeEntry([]byte(fileMagic + "{\n"))
+
+		assert.Equal(t, ErrorCorruptEntry, err)
+	})
+
+	t.Run("Reads header of entry", func(t *testing.T) {
+		defer deleteCacheDir()
+		os.Mkdir(cacheDir, 0755)
+		filePath := path.Join(cacheDir, "file")
+		data := gotils.ResultOrPanic(encodeEntry(&fileHeader{Key: "beer"}, []byte("payload")))
+		gotils.NilOrPanic(os.WriteFile(filePath, data, 0644))
+
+		header, err := readEntryHeader(filePath)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "beer", header.Key)
+	})
+
+	t.Run("Reads no header of legacy entry", func(t *testing.T) {
+		defer deleteCacheDir()
+		os.Mkdir(cacheDir, 0755)
+		filePath := path.Join(cacheDir, "file")
+		gotils.NilOrPanic(os.WriteFile(filePath, []byte("legacy"), 0644))
+
+		header, err := readEntryHeader(filePath)
+
+		assert.Nil(t, err)
+		assert.Nil(t, header)
+	})
+
+	t.Run("Returns error if header can't be read", func(t *testing.T) {
+		defer deleteCacheDir()
+		os.Mkdir(cacheDir, 0755)
+		filePath := path.Join(cacheDir, "file")
+		gotils.NilOrPanic(os.WriteFile(filePath, []byte(fileMagic+"{"), 0644))
+
+		_, truncatedErr := readEntryHeader(filePath)
+		gotils.NilOrPanic(os.WriteFile(filePath, []byte(fileMagic+"{\n"), 0644))
+		_, invalidErr := readEntryHeader(filePath)
+		_, missingErr := readEntryHeader(path.Join(cacheDir, "missing"))
+
+		assert.Equal(t, ErrorCorruptEntry, truncatedErr)
+		assert.Equal(t, ErrorCorruptEntry, invalidErr)
+		assert.True(t, os.IsNotExist(missingErr))
+	})
+
+	t.Run("Writes file atomically", func(t *testing.T) {
+		defer deleteCacheDir()
+		os.Mkdir(cacheDir, 0755)
+		filePath := path.Join(cacheDir, "file")
+
+		gotils.NilOrPanic(writeFileAtomic(filePath, []byte("long content")))
+		gotils.NilOrPanic(writeFileAtomic(filePath, []byte("short")))
+
+		assert.Equal(t, []byte("short"), gotils.ResultOrPanic(os.ReadFile(filePath)))
+		assert.Equal(t, 1, len(gotils.ResultOrPanic(os.ReadDir(cacheDir))))
+	})
+
+	t.Run("Returns error if directory not exists", func(t *testing.T) {
+		assert.Error(t, writeFileAtomic("/var/this_directory_does_not_exists/file", []byte("")))
+	})
+}
@@ -0,0 +1,196 @@
+package cache
+
+import (
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const metadataSuffix = ".meta"
+
+const staleTmpFileAge = 1 * time.Hour
+
+type FileCacheEntry struct {
+	Key     string
+	Hash    string
+	Size    int64
+	ModTime time.Time
+	path    string
+}
+
+type FileCacheStats struct {
+	Entries   int
+	TotalSize int64
+}
+
+func MigrateFileCache(workdir string) (int, error) {
+	dirEntries, err := os.ReadDir(workdir)
+	if err != nil {
+		return 0, err
+	}
+
+	migrated := 0
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		hash := strings.TrimSuffix(name, metadataSuffix)
+		if dirEntry.IsDir() || !isEntryHash(hash) {
+			continue
+		}
+
+		newPath := getShardedPath(workdir, hash) + strings.TrimPrefix(name, hash)
+		if err := createShardDir(newPath); err != nil {
+			return migrated, err
+		}
+
+		if err := os.Rename(filepath.Join(workdir, name), newPath); err != nil {
+			return migrated, err
+		}
+
+		if hash == name {
+			migrated++
+		}
+	}
+
+	return migrated, nil
+}
+
+func ListFileCacheEntries(workdir string) ([]*FileCacheEntry, error) {
+	entries := []*FileCacheEntry{}
+	err := filepath.WalkDir(workdir, func(filePath string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if dirEntry.IsDir() || !isEntryHash(dirEntry.Name()) {
+			return nil
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			return err
+		}
+
+		entry := &FileCacheEntry{
+			Hash:    dirEntry.Name(),
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+			path:    filePath,
+		}
+
+		if header, err := readEntryHeader(filePath); err == nil && header != nil {
+			entry.Key = header.Key
+		}
+
+		if metaInfo, err := os.Stat(filePath + metadataSuffix); err == nil {
+			entry.Size += metaInfo.Size()
+			if metaInfo.ModTime().After(entry.ModTime) {
+				entry.ModTime = metaInfo.ModTime()
+			}
+		}
+
+		entries = append(entries, entry)
+		return nil
+	})
+
+	return entries, err
+}
+
+func GetFileCacheStats(workdir string) (*FileCacheStats, error) {
+	entries, err := ListFileCacheEntries(workdir)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &FileCacheStats{}
+	for _, entry := range entries {
+		stats.Entries++
+		stats.TotalSize += entry.Size
+	}
+
+	return stats, nil
+}
+
+func CollectFileCacheGarbage(workdir string, maxAge time.Duration, maxSize int64) (int, error) {
+	if err := removeStaleTmpFiles(workdir); err != nil {
+		return 0, err
+	}
+
+	entries, err := ListFileCacheEntries(workdir)
+	if err != nil {
+		return 0, err
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].ModTime.Before(entries[j].ModTime)
+	})
+
+	var totalSize int64
+	for _, entry := range entries {
+		totalSize += entry.Size
+	}
+
+	deleted := 0
+	now := time.Now()
+	for _, entry := range entries {
+		tooOld := maxAge > 0 && now.Sub(entry.ModTime) > maxAge
+		tooLarge := maxSize > 0 && totalSize > maxSize
+
+		if !tooOld && !tooLarge {
+			break
+		}
+
+		if err := removeEntry(entry.path); err != nil {
+			return deleted, err
+		}
+
+		totalSize -= entry.Size
+		deleted++
+	}
+
+	return deleted, nil
+}
+
+func removeEntry(filePath string) error {
+	if err := os.Remove(filePath + metadataSuffix); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	return os.Remove(filePath)
+}
+
+func removeStaleTmpFiles(workdir string) error {
+	now := time.Now()
+	return filepath.WalkDir(workdir, func(filePath string, dirEntry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if dirEntry.IsDir() || !strings.HasPrefix(dirEntry.Name(), ".tmp-") {
+			return nil
+		}
+
+		info, err := dirEntry.Info()
+		if err != nil {
+			return err
+		}
+
+		if now.Sub(info.ModTime()) > staleTmpFileAge {
+			return os.Remove(filePath)
+		}
+
+		return nil
+	})
+}
+
+func isEntryHash(name string) bool {
+	if len(name) != 32 {
+		return false
+	}
+
+	_, err := hex.DecodeString(name)
+	return err == nil
+}
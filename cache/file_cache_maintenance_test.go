@@ -0,0 +1,152 @@
+package cache
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileCacheMaintenance(t *testing.T) {
+	getHash := func(key string) string {
+		hash := md5.Sum([]byte(key))
+		return hex.EncodeToString(hash[:])
+	}
+
+	setModTime := func(c ICache, key string, modTime time.Time) {
+		filePath := c.(*fileCache).getFilePath(key)
+		gotils.NilOrPanic(os.Chtimes(filePath, modTime, modTime))
+		gotils.NilOrPanic(os.Chtimes(filePath+metadataSuffix, modTime, modTime))
+	}
+
+	t.Run("Migrates flat layout", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache()
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(c.(IMetadataCache).SetMetadata("beer", &Metadata{ETag: "42"}))
+		filePath := c.(*fileCache).getFilePath("beer")
+		flatPath := path.Join(cacheDir, getHash("beer"))
+		gotils.NilOrPanic(os.Rename(filePath, flatPath))
+		gotils.NilOrPanic(os.Rename(filePath+metadataSuffix, flatPath+metadataSuffix))
+		gotils.NilOrPanic(os.WriteFile(path.Join(cacheDir, "unrelated"), []byte{}, 0644))
+
+		migrated, err := MigrateFileCache(cacheDir)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, migrated)
+		assert.Equal(t, "lager", gotils.ResultOrPanic(c.Get("beer")))
+		assert.Equal(t, "42", gotils.ResultOrPanic(c.(IMetadataCache).GetMetadata("beer")).ETag)
+	})
+
+	t.Run("Returns error if migrated directory not exists", func(t *testing.T) {
+		_, err := MigrateFileCache("/var/this_directory_does_not_exists")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Lists entries and computes stats", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache()
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(c.Set("wine", "red"))
+
+		entries, err := ListFileCacheEntries(cacheDir)
+		stats := gotils.ResultOrPanic(GetFileCacheStats(cacheDir))
+
+		assert.Nil(t, err)
+		hashes := []string{}
+		keys := []string{}
+		var totalSize int64
+		for _, entry := range entries {
+			hashes = append(hashes, entry.Hash)
+			keys = append(keys, entry.Key)
+			totalSize += entry.Size
+		}
+		assert.ElementsMatch(t, []string{getHash("beer"), getHash("wine")}, hashes)
+		assert.ElementsMatch(t, []string{"beer", "wine"}, keys)
+		assert.Equal(t, &FileCacheStats{Entries: 2, TotalSize: totalSize}, stats)
+	})
+
+	t.Run("Returns error if listed directory not exists", func(t *testing.T) {
+		_, err := GetFileCacheStats("/var/this_directory_does_not_exists")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Collects entries older than max age", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache()
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(c.Set("wine", "red"))
+		setModTime(c, "beer", time.Now().Add(-2*time.Hour))
+
+		deleted, err := CollectFileCacheGarbage(cacheDir, time.Hour, 0)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, deleted)
+		assert.False(t, c.Has("beer"))
+		assert.True(t, c.Has("wine"))
+		_, metaErr := c.(IMetadataCache).GetMetadata("beer")
+		assert.Error(t, metaErr)
+	})
+
+	t.Run("Keeps old entries with recently revalidated metadata", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache()
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		setModTime(c, "beer", time.Now().Add(-2*time.Hour))
+		gotils.NilOrPanic(c.(IMetadataCache).SetMetadata("beer", &Metadata{FetchedAt: time.Now()}))
+
+		deleted, err := CollectFileCacheGarbage(cacheDir, time.Hour, 0)
+		entries := gotils.ResultOrPanic(ListFileCacheEntries(cacheDir))
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, deleted)
+		assert.True(t, c.Has("beer"))
+		assert.WithinDuration(t, time.Now(), entries[0].ModTime, time.Minute)
+	})
+
+	t.Run("Collects oldest entries beyond size quota", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache()
+		for i, key := range []string{"a", "b", "c"} {
+			gotils.NilOrPanic(c.Set(key, "value"))
+			setModTime(c, key, time.Now().Add(time.Duration(i-3)*time.Minute))
+		}
+		stats := gotils.ResultOrPanic(GetFileCacheStats(cacheDir))
+
+		deleted, err := CollectFileCacheGarbage(cacheDir, 0, stats.TotalSize-1)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, deleted)
+		assert.False(t, c.Has("a"))
+		assert.True(t, c.Has("b"))
+		assert.True(t, c.Has("c"))
+	})
+
+	t.Run("Removes stale temporary files", func(t *testing.T) {
+		defer deleteCacheDir()
+		os.Mkdir(cacheDir, 0755)
+		tmpPath := path.Join(cacheDir, ".tmp-1")
+		gotils.NilOrPanic(os.WriteFile(tmpPath, []byte{}, 0644))
+		modTime := time.Now().Add(-2 * staleTmpFileAge)
+		gotils.NilOrPanic(os.Chtimes(tmpPath, modTime, modTime))
+
+		_, err := CollectFileCacheGarbage(cacheDir, 0, 0)
+
+		assert.Nil(t, err)
+		_, statErr := os.Stat(tmpPath)
+		assert.True(t, os.IsNotExist(statErr))
+	})
+
+	t.Run("Returns error if collected directory not exists", func(t *testing.T) {
+		_, err := CollectFileCacheGarbage("/var/this_directory_does_not_exists", 0, 0)
+
+		assert.Error(t, err)
+	})
+}
@@ -2,12 +2,21 @@ package cache
 
 import (
 	"bytes"
+	"compress/gzip"
 	"crypto/md5"
 	"encoding/hex"
+	"fmt"
 	"io"
+	"io/fs"
+	"net/http"
 	"os"
 	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/DAtek/gotils"
 	"github.com/andybalholm/brotli"
@@ -31,7 +40,8 @@ func TestFileCacheGet(t *testing.T) {
 		value := "42"
 		hash := md5.Sum([]byte(key))
 		filename := hex.EncodeToString(hash[:])
-		filePath := path.Join(cacheDir, filename)
+		filePath := path.Join(cacheDir, filename[0:2], filename[2:4], filename)
+		gotils.NilOrPanic(os.MkdirAll(path.Dir(filePath), 0755))
 
 		file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY, 0755)
 		if err != nil {
@@ -48,6 +58,20 @@ func TestFileCacheGet(t *testing.T) {
 		assert.Equal(t, value, res)
 	})
 
+	t.Run("Returns error if cached data is corrupt", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache()
+		key := "meaning of life"
+		gotils.NilOrPanic(c.Set(key, "42"))
+		filePath := c.(*fileCache).getFilePath(key)
+		data := gotils.ResultOrPanic(os.ReadFile(filePath))
+		gotils.NilOrPanic(os.WriteFile(filePath, data[:len(data)-1], 0644))
+
+		_, err := c.Get(key)
+
+		assert.Equal(t, ErrorCorruptEntry, err)
+	})
+
 	t.Run("Returns error if cached data is not compressed", func(t *testing.T) {
 		defer deleteCacheDir()
 		c := newCache()
@@ -55,7 +79,8 @@ func TestFileCacheGet(t *testing.T) {
 		value := "42"
 		hash := md5.Sum([]byte(key))
 		filename := hex.EncodeToString(hash[:])
-		filePath := path.Join(cacheDir, filename)
+		filePath := path.Join(cacheDir, filename[0:2], filename[2:4], filename)
+		gotils.NilOrPanic(os.MkdirAll(path.Dir(filePath), 0755))
 
 		file := gotils.ResultOrPanic(os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY, 0755))
 		file.WriteString(value)
@@ -74,13 +99,14 @@ func TestFileCacheHas(t *testing.T) {
 		assert.False(t, c.Has("something"))
 	})
 
-	t.Run("False if file not exists", func(t *testing.T) {
+	t.Run("False if file is empty", func(t *testing.T) {
 		c := newCache()
 		defer deleteCacheDir()
 		key := "meaning of life"
 		hash := md5.Sum([]byte(key))
 		filename := hex.EncodeToString(hash[:])
-		filePath := path.Join(cacheDir, filename)
+		filePath := path.Join(cacheDir, filename[0:2], filename[2:4], filename)
+		gotils.NilOrPanic(os.MkdirAll(path.Dir(filePath), 0755))
 
 		f, err := os.Create(filePath)
 		if err != nil {
@@ -88,7 +114,57 @@ func TestFileCacheHas(t *testing.T) {
 		}
 		f.Close()
 
+		assert.False(t, c.Has(key))
+	})
+
+	t.Run("True if file exists", func(t *testing.T) {
+		c := newCache()
+		defer deleteCacheDir()
+		key := "meaning of life"
+		gotils.NilOrPanic(c.Set(key, "42"))
+
+		assert.True(t, c.Has(key))
+	})
+}
+
+func TestFileCacheHasCorruptEntry(t *testing.T) {
+	t.Run("False if entry header is corrupt", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache()
+		key := "meaning of life"
+		gotils.NilOrPanic(c.Set(key, "42"))
+		filePath := c.(*fileCache).getFilePath(key)
+		gotils.NilOrPanic(os.WriteFile(filePath, []byte(fileMagic+"{not json\n"), 0644))
+
+		assert.False(t, c.Has(key))
+	})
+
+	t.Run("True for legacy entry without header", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache()
+		key := "meaning of life"
+		gotils.NilOrPanic(c.Set(key, "42"))
+		filePath := c.(*fileCache).getFilePath(key)
+		gotils.NilOrPanic(os.WriteFile(filePath, gotils.ResultOrPanic(compress(CodecBrotli, 5, []byte("42"))), 0644))
+
 		assert.True(t, c.Has(key))
+		assert.Equal(t, "42", gotils.ResultOrPanic(c.Get(key)))
+	})
+
+	t.Run("False if entry payload is corrupt", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache()
+		key := "meaning of life"
+		gotils.NilOrPanic(c.Set(key, "42"))
+		filePath := c.(*fileCache).getFilePath(key)
+		data := gotils.ResultOrPanic(os.ReadFile(filePath))
+		data[len(data)-1]++
+		gotils.NilOrPanic(os.WriteFile(filePath, data, 0644))
+
+		_, err := c.Get(key)
+
+		assert.False(t, c.Has(key))
+		assert.Equal(t, ErrorCorruptEntry, err)
 	})
 }
 
@@ -108,7 +184,8 @@ func TestFileCacheDelete(t *testing.T) {
 		key := "meaning of life"
 		hash := md5.Sum([]byte(key))
 		filename := hex.EncodeToString(hash[:])
-		filePath := path.Join(cacheDir, filename)
+		filePath := path.Join(cacheDir, filename[0:2], filename[2:4], filename)
+		gotils.NilOrPanic(os.MkdirAll(path.Dir(filePath), 0755))
 
 		file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY, 0755)
 		if err != nil {
@@ -135,15 +212,53 @@ func TestFileCacheSet(t *testing.T) {
 
 		hash := md5.Sum([]byte(key))
 		filename := hex.EncodeToString(hash[:])
-		filePath := path.Join(cacheDir, filename)
-		file, _ := os.Open(filePath)
-		reader := brotli.NewReader(file)
+		filePath := path.Join(cacheDir, filename[0:2], filename[2:4], filename)
+		data := gotils.ResultOrPanic(os.ReadFile(filePath))
+		_, payload, err := decodeEntry(data)
+		gotils.NilOrPanic(err)
+		reader := brotli.NewReader(bytes.NewReader(payload))
 		decompressedBuf := &bytes.Buffer{}
 		io.Copy(decompressedBuf, reader)
 
 		assert.Equal(t, value, decompressedBuf.String())
 	})
 
+	t.Run("Overwrites longer value", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache()
+		key := "beer"
+
+		gotils.NilOrPanic(c.Set(key, strings.Repeat("lager", 1000)))
+		gotils.NilOrPanic(c.Set(key, "ale"))
+
+		assert.Equal(t, "ale", gotils.ResultOrPanic(c.Get(key)))
+	})
+
+	t.Run("Leaves no temporary files behind", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache()
+
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		filepath.WalkDir(cacheDir, func(filePath string, entry fs.DirEntry, err error) error {
+			assert.False(t, strings.HasPrefix(entry.Name(), ".tmp-"))
+			return nil
+		})
+	})
+
+	t.Run("Stores item in sharded directory", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache()
+		key := "beer"
+
+		gotils.NilOrPanic(c.Set(key, "lager"))
+
+		hash := md5.Sum([]byte(key))
+		filename := hex.EncodeToString(hash[:])
+		_, err := os.Stat(path.Join(cacheDir, filename[0:2], filename[2:4], filename))
+		assert.Nil(t, err)
+	})
+
 	t.Run("Returns error if can't open file", func(t *testing.T) {
 		c := NewFileCache("/var/this_directory_does_not_exists")
 
@@ -151,13 +266,255 @@ func TestFileCacheSet(t *testing.T) {
 	})
 }
 
+func TestFileCacheMetadata(t *testing.T) {
+	t.Run("Returns stored metadata", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache().(IMetadataCache)
+		key := "beer"
+		meta := &Metadata{
+			FetchedAt:    time.Date(2015, 10, 21, 7, 28, 0, 0, time.UTC),
+			StatusCode:   200,
+			Header:       http.Header{"Content-Type": {"text/html"}},
+			ETag:         `"v1"`,
+			LastModified: "Wed, 21 Oct 2015 07:28:00 GMT",
+		}
+		gotils.NilOrPanic(c.Set(key, "lager"))
+
+		gotils.NilOrPanic(c.SetMetadata(key, meta))
+		res, err := c.GetMetadata(key)
+
+		assert.Nil(t, err)
+		assert.Equal(t, meta, res)
+	})
+
+	t.Run("Returns error if metadata not found", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache().(IMetadataCache)
+
+		_, err := c.GetMetadata("beer")
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Setting the value records fetch time", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache().(IMetadataCache)
+		before := time.Now()
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		meta, err := c.GetMetadata("beer")
+
+		assert.Nil(t, err)
+		assert.False(t, meta.FetchedAt.Before(before))
+	})
+
+	t.Run("Returns error if key not cached", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache().(IMetadataCache)
+
+		assert.Error(t, c.SetMetadata("beer", &Metadata{}))
+	})
+
+	t.Run("Setting the value drops stale metadata", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache().(IMetadataCache)
+		key := "beer"
+		gotils.NilOrPanic(c.Set(key, "lager"))
+		gotils.NilOrPanic(c.SetMetadata(key, &Metadata{ETag: `"v1"`}))
+
+		gotils.NilOrPanic(c.Set(key, "stout"))
+		meta, err := c.GetMetadata(key)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "", meta.ETag)
+	})
+
+	t.Run("Deleting the key deletes metadata", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache().(IMetadataCache)
+		key := "beer"
+		gotils.NilOrPanic(c.Set(key, "lager"))
+		gotils.NilOrPanic(c.SetMetadata(key, &Metadata{ETag: `"v1"`}))
+
+		gotils.NilOrPanic(c.Delete(key))
+		_, err := c.GetMetadata(key)
+
+		assert.Error(t, err)
+	})
+}
+
+func TestFileCacheKeys(t *testing.T) {
+	t.Run("Returns original keys", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache().(IIterableCache)
+		gotils.NilOrPanic(c.Set("http://demo.example/1", "1"))
+		gotils.NilOrPanic(c.Set("http://demo.example/2", "2"))
+
+		keys, err := c.Keys()
+
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"http://demo.example/1", "http://demo.example/2"}, keys)
+	})
+
+	t.Run("Skips legacy entries without key", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache().(IIterableCache)
+		filePath := c.(*fileCache).getFilePath("legacy")
+		gotils.NilOrPanic(os.MkdirAll(path.Dir(filePath), 0755))
+		gotils.NilOrPanic(os.WriteFile(filePath, []byte("legacy"), 0644))
+
+		keys, err := c.Keys()
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{}, keys)
+	})
+
+	t.Run("Returns error if directory not exists", func(t *testing.T) {
+		c := NewFileCache("/var/this_directory_does_not_exists").(IIterableCache)
+
+		_, err := c.Keys()
+
+		assert.Error(t, err)
+	})
+}
+
+func TestFileCacheCodec(t *testing.T) {
+	codecs := []CompressionOption{
+		WithCodec(CodecBrotli, brotli.BestSpeed),
+		WithCodec(CodecGzip, gzip.DefaultCompression),
+		WithCodec(CodecZstd, 3),
+		WithCodec(CodecNone, 0),
+	}
+
+	t.Run("Reads entries written with any codec", func(t *testing.T) {
+		defer deleteCacheDir()
+		newCache()
+
+		for i, codec := range codecs {
+			gotils.NilOrPanic(NewFileCache(cacheDir, codec).Set(strconv.Itoa(i), "value"+strconv.Itoa(i)))
+		}
+
+		c := NewFileCache(cacheDir)
+		for i := range codecs {
+			value, err := c.Get(strconv.Itoa(i))
+
+			assert.Nil(t, err)
+			assert.Equal(t, "value"+strconv.Itoa(i), value)
+		}
+	})
+
+	t.Run("Stores codec in header", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := NewFileCache(newCacheDir(), WithCodec(CodecNone, 0))
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		header := gotils.ResultOrPanic(readEntryHeader(c.(*fileCache).getFilePath("beer")))
+
+		assert.Equal(t, CodecNone, header.Codec)
+	})
+
+	t.Run("Returns error if codec is unsupported", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := NewFileCache(newCacheDir(), WithCodec("lzma", 0))
+
+		assert.Equal(t, ErrorUnsupportedCodec, c.Set("beer", "lager"))
+	})
+}
+
+func TestFileCacheConcurrency(t *testing.T) {
+	t.Run("Concurrent writes and reads of different keys are consistent", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := newCache()
+		wg := &sync.WaitGroup{}
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 10; j++ {
+					key := fmt.Sprintf("%d-%d", i, j)
+					gotils.NilOrPanic(c.Set(key, key))
+					assert.Equal(t, key, gotils.ResultOrPanic(c.Get(key)))
+				}
+			}(i)
+		}
+
+		wg.Wait()
+	})
+}
+
+func BenchmarkFileCache(b *testing.B) {
+	page := strings.Repeat("<div class=\"article\"><p>Lorem ipsum dolor sit amet</p></div>\n", 200)
+
+	for _, workers := range []int{1, 8, 32} {
+		b.Run(fmt.Sprintf("workers-%d", workers), func(b *testing.B) {
+			defer deleteCacheDir()
+			c := newCache()
+			wg := &sync.WaitGroup{}
+			b.SetBytes(int64(len(page)))
+			b.ResetTimer()
+
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := w; i < b.N; i += workers {
+						key := strconv.Itoa(i % 1000)
+						gotils.NilOrPanic(c.Set(key, page))
+						gotils.ResultOrPanic(c.Get(key))
+					}
+				}(w)
+			}
+
+			wg.Wait()
+		})
+	}
+}
+
+func BenchmarkFileCacheCodec(b *testing.B) {
+	page := strings.Repeat("<div class=\"article\"><p>Lorem ipsum dolor sit amet</p></div>\n", 200)
+	codecs := []struct {
+		name  string
+		codec Codec
+		level int
+	}{
+		{"brotli-best", CodecBrotli, brotli.BestCompression},
+		{"brotli-default", CodecBrotli, brotli.DefaultCompression},
+		{"brotli-fast", CodecBrotli, brotli.BestSpeed},
+		{"gzip-default", CodecGzip, gzip.DefaultCompression},
+		{"gzip-fast", CodecGzip, gzip.BestSpeed},
+		{"zstd-default", CodecZstd, 3},
+		{"zstd-fast", CodecZstd, 1},
+		{"none", CodecNone, 0},
+	}
+
+	for _, codec := range codecs {
+		b.Run(codec.name, func(b *testing.B) {
+			defer deleteCacheDir()
+			c := NewFileCache(newCacheDir(), WithCodec(codec.codec, codec.level))
+			b.SetBytes(int64(len(page)))
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				key := strconv.Itoa(i % 1000)
+				gotils.NilOrPanic(c.Set(key, page))
+				gotils.ResultOrPanic(c.Get(key))
+			}
+		})
+	}
+}
+
 var tmpDir = os.Getenv("TMP_DIR")
 
 var cacheDir = path.Join(tmpDir, "cache")
 
 func newCache() ICache {
+	return NewFileCache(newCacheDir())
+}
+
+func newCacheDir() string {
 	os.Mkdir(cacheDir, 0755)
-	return NewFileCache(cacheDir)
+	return cacheDir
 }
 
 func deleteCacheDir() {
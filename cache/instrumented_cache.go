@@ -0,0 +1,203 @@
+package cache
+
+import (
+	"errors"
+	"io/fs"
+	"sync/atomic"
+	"time"
+)
+
+var DefaultLatencyBounds = []time.Duration{
+	100 * time.Microsecond,
+	1 * time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	1 * time.Second,
+	10 * time.Second,
+}
+
+type LatencyHistogram struct {
+	Bounds []time.Duration
+	Counts []int64
+	Count  int64
+	Sum    time.Duration
+}
+
+func (h LatencyHistogram) Mean() time.Duration {
+	if h.Count == 0 {
+		return 0
+	}
+
+	return h.Sum / time.Duration(h.Count)
+}
+
+type CacheStats struct {
+	Hits         int64
+	Misses       int64
+	Lookups      int64
+	Sets         int64
+	Deletes      int64
+	Errors       int64
+	BytesRead    int64
+	BytesWritten int64
+	GetLatency   LatencyHistogram
+	SetLatency   LatencyHistogram
+}
+
+type IStatsCache interface {
+	ICache
+	Stats() CacheStats
+}
+
+type IInstrumentedCache interface {
+	IMetadataCache
+	IIterableCache
+	Stats() CacheStats
+}
+
+type latencyHistogram struct {
+	bounds []time.Duration
+	counts []atomic.Int64
+	sum    atomic.Int64
+}
+
+func newLatencyHistogram(bounds []time.Duration) *latencyHistogram {
+	return &latencyHistogram{
+		bounds: bounds,
+		counts: make([]atomic.Int64, len(bounds)+1),
+	}
+}
+
+func (h *latencyHistogram) observe(duration time.Duration) {
+	i := 0
+	for i < len(h.bounds) && duration > h.bounds[i] {
+		i++
+	}
+
+	h.counts[i].Add(1)
+	h.sum.Add(int64(duration))
+}
+
+func (h *latencyHistogram) snapshot() LatencyHistogram {
+	result := LatencyHistogram{
+		Bounds: append([]time.Duration{}, h.bounds...),
+		Counts: make([]int64, len(h.counts)),
+		Sum:    time.Duration(h.sum.Load()),
+	}
+
+	for i := range h.counts {
+		result.Counts[i] = h.counts[i].Load()
+		result.Count += result.Counts[i]
+	}
+
+	return result
+}
+
+type instrumentedCache struct {
+	store        ICache
+	hits         atomic.Int64
+	misses       atomic.Int64
+	lookups      atomic.Int64
+	sets         atomic.Int64
+	deletes      atomic.Int64
+	errors       atomic.Int64
+	bytesRead    atomic.Int64
+	bytesWritten atomic.Int64
+	getLatency   *latencyHistogram
+	setLatency   *latencyHistogram
+}
+
+func NewInstrumentedCache(store ICache) IInstrumentedCache {
+	return &instrumentedCache{
+		store:      store,
+		getLatency: newLatencyHistogram(DefaultLatencyBounds),
+		setLatency: newLatencyHistogram(DefaultLatencyBounds),
+	}
+}
+
+func (c *instrumentedCache) Get(key string) (string, error) {
+	started := time.Now()
+	val, err := c.store.Get(key)
+	c.getLatency.observe(time.Since(started))
+
+	switch {
+	case err == nil:
+		c.hits.Add(1)
+		c.bytesRead.Add(int64(len(val)))
+	case IsNotFound(err):
+		c.misses.Add(1)
+	default:
+		c.errors.Add(1)
+	}
+
+	return val, err
+}
+
+func (c *instrumentedCache) Set(key string, val string) error {
+	started := time.Now()
+	err := c.store.Set(key, val)
+	c.setLatency.observe(time.Since(started))
+
+	if err != nil {
+		c.errors.Add(1)
+		return err
+	}
+
+	c.sets.Add(1)
+	c.bytesWritten.Add(int64(len(val)))
+	return nil
+}
+
+func (c *instrumentedCache) Has(key string) bool {
+	c.lookups.Add(1)
+	return c.store.Has(key)
+}
+
+func (c *instrumentedCache) Delete(key string) error {
+	err := c.store.Delete(key)
+
+	switch {
+	case err == nil:
+		c.deletes.Add(1)
+	case !IsNotFound(err):
+		c.errors.Add(1)
+	}
+
+	return err
+}
+
+func (c *instrumentedCache) GetMetadata(key string) (*Metadata, error) {
+	return getMetadata(c.store, key)
+}
+
+func (c *instrumentedCache) SetMetadata(key string, meta *Metadata) error {
+	err := setMetadata(c.store, key, meta)
+	if err != nil {
+		c.errors.Add(1)
+	}
+
+	return err
+}
+
+func (c *instrumentedCache) Keys() ([]string, error) {
+	return getKeys(c.store)
+}
+
+func (c *instrumentedCache) Stats() CacheStats {
+	return CacheStats{
+		Hits:         c.hits.Load(),
+		Misses:       c.misses.Load(),
+		Lookups:      c.lookups.Load(),
+		Sets:         c.sets.Load(),
+		Deletes:      c.deletes.Load(),
+		Errors:       c.errors.Load(),
+		BytesRead:    c.bytesRead.Load(),
+		BytesWritten: c.bytesWritten.Load(),
+		GetLatency:   c.getLatency.snapshot(),
+		SetLatency:   c.setLatency.snapshot(),
+	}
+}
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrorKeyNotFound) || errors.Is(err, fs.ErrNotExist)
+}
@@ -0,0 +1,116 @@
+package cache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInstrumentedCache(t *testing.T) {
+	t.Run("Counts hits, misses, lookups, sets and bytes", func(t *testing.T) {
+		c := NewInstrumentedCache(NewMemoryCache(0, 0))
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.ResultOrPanic(c.Get("beer"))
+		c.Has("beer")
+		c.Has("wine")
+		c.Get("wine")
+		gotils.NilOrPanic(c.Delete("beer"))
+		c.Delete("beer")
+
+		stats := c.Stats()
+
+		assert.Equal(t, int64(1), stats.Hits)
+		assert.Equal(t, int64(1), stats.Misses)
+		assert.Equal(t, int64(2), stats.Lookups)
+		assert.Equal(t, int64(1), stats.Sets)
+		assert.Equal(t, int64(1), stats.Deletes)
+		assert.Equal(t, int64(0), stats.Errors)
+		assert.Equal(t, int64(5), stats.BytesRead)
+		assert.Equal(t, int64(5), stats.BytesWritten)
+	})
+
+	t.Run("Counts missing files as misses", func(t *testing.T) {
+		defer deleteCacheDir()
+		c := NewInstrumentedCache(newCache())
+
+		_, err := c.Get("beer")
+
+		assert.Error(t, err)
+		assert.Equal(t, int64(1), c.Stats().Misses)
+		assert.Equal(t, int64(0), c.Stats().Errors)
+	})
+
+	t.Run("Counts errors", func(t *testing.T) {
+		err := errors.New("UNEXPECTED_ERROR")
+		c := NewInstrumentedCache(&MockMetadataCache{
+			MockCache: MockCache{
+				Get_:    func(key string) (string, error) { return "", err },
+				Set_:    func(key, val string) error { return err },
+				Delete_: func(key string) error { return err },
+			},
+			SetMetadata_: func(key string, meta *Metadata) error { return err },
+		})
+
+		_, getErr := c.Get("beer")
+
+		assert.Equal(t, err, getErr)
+		assert.Equal(t, err, c.Set("beer", "lager"))
+		assert.Equal(t, err, c.Delete("beer"))
+		assert.Equal(t, err, c.SetMetadata("beer", &Metadata{}))
+		assert.Equal(t, int64(4), c.Stats().Errors)
+		assert.Equal(t, int64(0), c.Stats().Sets)
+	})
+
+	t.Run("Records latency histograms", func(t *testing.T) {
+		c := NewInstrumentedCache(&MockCache{
+			Get_: func(key string) (string, error) {
+				time.Sleep(2 * time.Millisecond)
+				return "lager", nil
+			},
+			Set_: func(key, val string) error { return nil },
+		})
+		gotils.ResultOrPanic(c.Get("beer"))
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(c.Set("wine", "red"))
+
+		stats := c.Stats()
+
+		assert.Equal(t, DefaultLatencyBounds, stats.GetLatency.Bounds)
+		assert.Equal(t, len(DefaultLatencyBounds)+1, len(stats.GetLatency.Counts))
+		assert.Equal(t, int64(1), stats.GetLatency.Count)
+		assert.Equal(t, int64(1), stats.GetLatency.Counts[2])
+		assert.GreaterOrEqual(t, stats.GetLatency.Mean(), 2*time.Millisecond)
+		assert.Equal(t, int64(2), stats.SetLatency.Count)
+		assert.Equal(t, time.Duration(0), LatencyHistogram{}.Mean())
+	})
+
+	t.Run("Passes metadata and keys through", func(t *testing.T) {
+		c := NewInstrumentedCache(NewMemoryCache(0, 0))
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		meta := &Metadata{ETag: `"v1"`}
+
+		gotils.NilOrPanic(c.SetMetadata("beer", meta))
+
+		assert.Equal(t, meta, gotils.ResultOrPanic(c.GetMetadata("beer")))
+		assert.Equal(t, []string{"beer"}, gotils.ResultOrPanic(c.Keys()))
+	})
+}
+
+func TestLatencyHistogram(t *testing.T) {
+	t.Run("Puts durations into buckets", func(t *testing.T) {
+		h := newLatencyHistogram([]time.Duration{time.Millisecond, time.Second})
+
+		h.observe(time.Microsecond)
+		h.observe(time.Millisecond)
+		h.observe(2 * time.Millisecond)
+		h.observe(time.Minute)
+
+		snapshot := h.snapshot()
+		assert.Equal(t, []int64{2, 1, 1}, snapshot.Counts)
+		assert.Equal(t, int64(4), snapshot.Count)
+		assert.Equal(t, time.Minute+3*time.Millisecond+time.Microsecond, snapshot.Sum)
+	})
+}
@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/DAtek/gotils"
+)
+
+const ErrorKeyNotFound = gotils.Error("KEY_NOT_FOUND")
+const ErrorValueTooLarge = gotils.Error("VALUE_TOO_LARGE")
+
+type memoryCacheEntry struct {
+	key  string
+	val  string
+	meta *Metadata
+}
+
+type memoryCache struct {
+	maxEntries int
+	maxBytes   int64
+	size       int64
+	entries    map[string]*list.Element
+	lru        *list.List
+	mutex      *sync.Mutex
+}
+
+func NewMemoryCache(maxEntries int, maxBytes int64) ICache {
+	return &memoryCache{
+		maxEntries: maxEntries,
+		maxBytes:   maxBytes,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+		mutex:      &sync.Mutex{},
+	}
+}
+
+func (c *memoryCache) Get(key string) (string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[key]
+
+	if !ok {
+		return "", ErrorKeyNotFound
+	}
+
+	c.lru.MoveToFront(element)
+	return element.Value.(*memoryCacheEntry).val, nil
+}
+
+func (c *memoryCache) Set(key string, val string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.remove(key)
+
+	if c.maxBytes > 0 && entrySize(key, val) > c.maxBytes {
+		return ErrorValueTooLarge
+	}
+
+	element := c.lru.PushFront(&memoryCacheEntry{
+		key:  key,
+		val:  val,
+		meta: &Metadata{FetchedAt: time.Now()},
+	})
+	c.entries[key] = element
+	c.size += entrySize(key, val)
+	c.evict()
+	return nil
+}
+
+func (c *memoryCache) Delete(key string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if !c.remove(key) {
+		return ErrorKeyNotFound
+	}
+
+	return nil
+}
+
+func (c *memoryCache) Has(key string) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	_, ok := c.entries[key]
+	return ok
+}
+
+func (c *memoryCache) GetMetadata(key string) (*Metadata, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[key]
+
+	if !ok {
+		return nil, ErrorKeyNotFound
+	}
+
+	meta := *element.Value.(*memoryCacheEntry).meta
+	return &meta, nil
+}
+
+func (c *memoryCache) SetMetadata(key string, meta *Metadata) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	element, ok := c.entries[key]
+
+	if !ok {
+		return ErrorKeyNotFound
+	}
+
+	metaCopy := *meta
+	element.Value.(*memoryCacheEntry).meta = &metaCopy
+	return nil
+}
+
+func (c *memoryCache) Keys() ([]string, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	keys := make([]string, 0, len(c.entries))
+
+	for key := range c.entries {
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (c *memoryCache) remove(key string) bool {
+	element, ok := c.entries[key]
+	if !ok {
+		return false
+	}
+
+	entry := c.lru.Remove(element).(*memoryCacheEntry)
+	delete(c.entries, key)
+	c.size -= entrySize(entry.key, entry.val)
+	return true
+}
+
+func (c *memoryCache) evict() {
+	for c.overLimit() {
+		c.remove(c.lru.Back().Value.(*memoryCacheEntry).key)
+	}
+}
+
+func (c *memoryCache) overLimit() bool {
+	tooManyEntries := c.maxEntries > 0 && c.lru.Len() > c.maxEntries
+	tooLarge := c.maxBytes > 0 && c.size > c.maxBytes
+	return tooManyEntries || tooLarge
+}
+
+func entrySize(key, val string) int64 {
+	return int64(len(key) + len(val))
+}
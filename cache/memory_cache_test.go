@@ -0,0 +1,150 @@
+package cache
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryCache(t *testing.T) {
+	t.Run("Returns stored value", func(t *testing.T) {
+		c := NewMemoryCache(0, 0)
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		val, err := c.Get("beer")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "lager", val)
+		assert.True(t, c.Has("beer"))
+	})
+
+	t.Run("Returns error if key not cached", func(t *testing.T) {
+		c := NewMemoryCache(0, 0)
+
+		_, err := c.Get("beer")
+
+		assert.Equal(t, ErrorKeyNotFound, err)
+		assert.False(t, c.Has("beer"))
+	})
+
+	t.Run("Deletes value", func(t *testing.T) {
+		c := NewMemoryCache(0, 0)
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		assert.Nil(t, c.Delete("beer"))
+		assert.False(t, c.Has("beer"))
+		assert.Equal(t, ErrorKeyNotFound, c.Delete("beer"))
+	})
+
+	t.Run("Evicts least recently used entry if entry count is exceeded", func(t *testing.T) {
+		c := NewMemoryCache(2, 0)
+		gotils.NilOrPanic(c.Set("a", "1"))
+		gotils.NilOrPanic(c.Set("b", "2"))
+		gotils.ResultOrPanic(c.Get("a"))
+
+		gotils.NilOrPanic(c.Set("c", "3"))
+
+		assert.True(t, c.Has("a"))
+		assert.False(t, c.Has("b"))
+		assert.True(t, c.Has("c"))
+	})
+
+	t.Run("Evicts least recently used entries if size is exceeded", func(t *testing.T) {
+		c := NewMemoryCache(0, 10)
+		gotils.NilOrPanic(c.Set("a", "1234"))
+		gotils.NilOrPanic(c.Set("b", "1234"))
+
+		gotils.NilOrPanic(c.Set("c", "1234"))
+
+		assert.False(t, c.Has("a"))
+		assert.True(t, c.Has("b"))
+		assert.True(t, c.Has("c"))
+	})
+
+	t.Run("Overwriting a value updates the size", func(t *testing.T) {
+		c := NewMemoryCache(0, 10)
+		gotils.NilOrPanic(c.Set("a", "12345678"))
+		gotils.NilOrPanic(c.Set("a", "1"))
+
+		gotils.NilOrPanic(c.Set("b", "1234"))
+
+		assert.True(t, c.Has("a"))
+		assert.True(t, c.Has("b"))
+	})
+
+	t.Run("Returns error if value is larger than the cache", func(t *testing.T) {
+		c := NewMemoryCache(0, 10)
+
+		assert.Equal(t, ErrorValueTooLarge, c.Set("a", "1234567890"))
+	})
+
+	t.Run("Removes previous value if new value is too large", func(t *testing.T) {
+		c := NewMemoryCache(0, 10)
+		gotils.NilOrPanic(c.Set("a", "1"))
+
+		err := c.Set("a", "1234567890")
+		_, getErr := c.Get("a")
+
+		assert.Equal(t, ErrorValueTooLarge, err)
+		assert.Equal(t, ErrorKeyNotFound, getErr)
+		assert.False(t, c.Has("a"))
+	})
+
+	t.Run("Stores metadata", func(t *testing.T) {
+		c := NewMemoryCache(0, 0).(IMetadataCache)
+		before := time.Now()
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		meta := gotils.ResultOrPanic(c.GetMetadata("beer"))
+		assert.False(t, meta.FetchedAt.Before(before))
+
+		gotils.NilOrPanic(c.SetMetadata("beer", &Metadata{ETag: "42"}))
+		meta = gotils.ResultOrPanic(c.GetMetadata("beer"))
+		assert.Equal(t, "42", meta.ETag)
+	})
+
+	t.Run("Returns error if metadata key not cached", func(t *testing.T) {
+		c := NewMemoryCache(0, 0).(IMetadataCache)
+
+		_, err := c.GetMetadata("beer")
+
+		assert.Equal(t, ErrorKeyNotFound, err)
+		assert.Equal(t, ErrorKeyNotFound, c.SetMetadata("beer", &Metadata{}))
+	})
+
+	t.Run("Returns keys", func(t *testing.T) {
+		c := NewMemoryCache(0, 0).(IIterableCache)
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(c.Set("wine", "red"))
+
+		keys, err := c.Keys()
+
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"beer", "wine"}, keys)
+	})
+
+	t.Run("Is safe for concurrent use", func(t *testing.T) {
+		c := NewMemoryCache(50, 0)
+		wg := &sync.WaitGroup{}
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				for j := 0; j < 100; j++ {
+					key := fmt.Sprintf("%d-%d", i, j)
+					gotils.NilOrPanic(c.Set(key, key))
+					c.Get(key)
+					c.Has(key)
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		assert.Equal(t, 50, c.(*memoryCache).lru.Len())
+	})
+}
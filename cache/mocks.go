@@ -22,3 +22,17 @@ func (m *MockCache) Has(key string) bool {
 func (m *MockCache) Delete(key string) error {
 	return m.Delete_(key)
 }
+
+type MockMetadataCache struct {
+	MockCache
+	GetMetadata_ func(key string) (*Metadata, error)
+	SetMetadata_ func(key string, meta *Metadata) error
+}
+
+func (m *MockMetadataCache) GetMetadata(key string) (*Metadata, error) {
+	return m.GetMetadata_(key)
+}
+
+func (m *MockMetadataCache) SetMetadata(key string, meta *Metadata) error {
+	return m.SetMetadata_(key, meta)
+}
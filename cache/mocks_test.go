@@ -55,3 +55,29 @@ func TestMockCache(t *testing.T) {
 		assert.Nil(t, c.Delete(""))
 	})
 }
+
+func TestMockMetadataCache(t *testing.T) {
+	t.Run("Test GetMetadata", func(t *testing.T) {
+		c := &MockMetadataCache{}
+
+		expectedMeta := &Metadata{ETag: "42"}
+		c.GetMetadata_ = func(key string) (*Metadata, error) {
+			return expectedMeta, nil
+		}
+
+		res, err := c.GetMetadata("")
+
+		assert.Nil(t, err)
+		assert.Equal(t, expectedMeta, res)
+	})
+
+	t.Run("Test SetMetadata", func(t *testing.T) {
+		c := &MockMetadataCache{}
+
+		c.SetMetadata_ = func(key string, meta *Metadata) error {
+			return nil
+		}
+
+		assert.Nil(t, c.SetMetadata("", &Metadata{}))
+	})
+}
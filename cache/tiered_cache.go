@@ -0,0 +1,260 @@
+package cache
+
+import (
+	"sync"
+)
+
+type WritePolicy int
+
+const (
+	WriteThrough = WritePolicy(iota)
+	WriteBack
+)
+
+type TieredCacheConfig struct {
+	Policy     WritePolicy
+	MaxPending int
+}
+
+func (c *TieredCacheConfig) validate() {
+	if c.MaxPending <= 0 {
+		c.MaxPending = 100
+	}
+}
+
+type ITieredCache interface {
+	IMetadataCache
+	IIterableCache
+	Flush() error
+}
+
+type pendingEntry struct {
+	val  string
+	meta *Metadata
+}
+
+type tieredCache struct {
+	fast    ICache
+	slow    ICache
+	config  *TieredCacheConfig
+	pending map[string]*pendingEntry
+	mutex   *sync.Mutex
+}
+
+func NewTieredCache(fast, slow ICache, config TieredCacheConfig) ITieredCache {
+	config.validate()
+	return &tieredCache{
+		fast:    fast,
+		slow:    slow,
+		config:  &config,
+		pending: map[string]*pendingEntry{},
+		mutex:   &sync.Mutex{},
+	}
+}
+
+func (c *tieredCache) Get(key string) (string, error) {
+	if val, err := c.fast.Get(key); err == nil {
+		return val, nil
+	}
+
+	if entry, ok := c.getPending(key); ok {
+		return entry.val, nil
+	}
+
+	val, err := c.slow.Get(key)
+	if err != nil {
+		return "", err
+	}
+
+	if err := c.fast.Set(key, val); err == nil {
+		if meta, err := getMetadata(c.slow, key); err == nil {
+			setMetadata(c.fast, key, meta)
+		}
+	}
+
+	return val, nil
+}
+
+func (c *tieredCache) Set(key string, val string) error {
+	if c.config.Policy == WriteThrough {
+		if err := c.slow.Set(key, val); err != nil {
+			return err
+		}
+
+		c.setFast(key, val)
+		return nil
+	}
+
+	c.setFast(key, val)
+	c.mutex.Lock()
+	c.pending[key] = &pendingEntry{val: val}
+	full := len(c.pending) >= c.config.MaxPending
+	c.mutex.Unlock()
+
+	if full {
+		return c.Flush()
+	}
+
+	return nil
+}
+
+func (c *tieredCache) Delete(key string) error {
+	c.mutex.Lock()
+	_, wasPending := c.pending[key]
+	delete(c.pending, key)
+	c.mutex.Unlock()
+
+	fastErr := c.fast.Delete(key)
+	slowErr := c.slow.Delete(key)
+
+	if slowErr != nil && fastErr != nil && !wasPending {
+		return slowErr
+	}
+
+	return nil
+}
+
+func (c *tieredCache) Has(key string) bool {
+	if c.fast.Has(key) {
+		return true
+	}
+
+	if _, ok := c.getPending(key); ok {
+		return true
+	}
+
+	return c.slow.Has(key)
+}
+
+func (c *tieredCache) GetMetadata(key string) (*Metadata, error) {
+	if meta, err := getMetadata(c.fast, key); err == nil {
+		return meta, nil
+	}
+
+	if entry, ok := c.getPending(key); ok && entry.meta != nil {
+		return entry.meta, nil
+	}
+
+	return getMetadata(c.slow, key)
+}
+
+func (c *tieredCache) SetMetadata(key string, meta *Metadata) error {
+	setMetadata(c.fast, key, meta)
+
+	c.mutex.Lock()
+	entry, ok := c.pending[key]
+	if ok {
+		entry.meta = meta
+	}
+	c.mutex.Unlock()
+
+	if ok {
+		return nil
+	}
+
+	return setMetadata(c.slow, key, meta)
+}
+
+func (c *tieredCache) Keys() ([]string, error) {
+	keySet := map[string]struct{}{}
+
+	for _, tier := range []ICache{c.fast, c.slow} {
+		keys, err := getKeys(tier)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, key := range keys {
+			keySet[key] = struct{}{}
+		}
+	}
+
+	c.mutex.Lock()
+	for key := range c.pending {
+		keySet[key] = struct{}{}
+	}
+	c.mutex.Unlock()
+
+	keys := make([]string, 0, len(keySet))
+	for key := range keySet {
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func (c *tieredCache) Flush() error {
+	c.mutex.Lock()
+	pending := c.pending
+	c.pending = map[string]*pendingEntry{}
+	c.mutex.Unlock()
+
+	for key, entry := range pending {
+		if err := c.slow.Set(key, entry.val); err != nil {
+			c.restorePending(pending)
+			return err
+		}
+
+		if entry.meta != nil {
+			if err := setMetadata(c.slow, key, entry.meta); err != nil {
+				c.restorePending(pending)
+				return err
+			}
+		}
+
+		delete(pending, key)
+	}
+
+	return nil
+}
+
+func (c *tieredCache) setFast(key string, val string) {
+	if err := c.fast.Set(key, val); err != nil {
+		c.fast.Delete(key)
+	}
+}
+
+func (c *tieredCache) getPending(key string) (*pendingEntry, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	entry, ok := c.pending[key]
+	return entry, ok
+}
+
+func (c *tieredCache) restorePending(pending map[string]*pendingEntry) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for key, entry := range pending {
+		if _, ok := c.pending[key]; !ok {
+			c.pending[key] = entry
+		}
+	}
+}
+
+func getMetadata(c ICache, key string) (*Metadata, error) {
+	metadataCache, ok := c.(IMetadataCache)
+	if !ok {
+		return nil, ErrorKeyNotFound
+	}
+
+	return metadataCache.GetMetadata(key)
+}
+
+func setMetadata(c ICache, key string, meta *Metadata) error {
+	metadataCache, ok := c.(IMetadataCache)
+	if !ok {
+		return nil
+	}
+
+	return metadataCache.SetMetadata(key, meta)
+}
+
+func getKeys(c ICache) ([]string, error) {
+	iterableCache, ok := c.(IIterableCache)
+	if !ok {
+		return nil, ErrorNotIterable
+	}
+
+	return iterableCache.Keys()
+}
@@ -0,0 +1,209 @@
+package cache
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTieredCache(t *testing.T) {
+	t.Run("Promotes value read from the slow cache", func(t *testing.T) {
+		fast := NewMemoryCache(0, 0)
+		slow := NewMemoryCache(0, 0).(IMetadataCache)
+		gotils.NilOrPanic(slow.Set("beer", "lager"))
+		gotils.NilOrPanic(slow.SetMetadata("beer", &Metadata{ETag: "42"}))
+		c := NewTieredCache(fast, slow, TieredCacheConfig{})
+
+		val, err := c.Get("beer")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "lager", val)
+		assert.Equal(t, "lager", gotils.ResultOrPanic(fast.Get("beer")))
+		assert.Equal(t, "42", gotils.ResultOrPanic(c.GetMetadata("beer")).ETag)
+	})
+
+	t.Run("Returns error if key not cached", func(t *testing.T) {
+		c := NewTieredCache(NewMemoryCache(0, 0), NewMemoryCache(0, 0), TieredCacheConfig{})
+
+		_, err := c.Get("beer")
+
+		assert.Equal(t, ErrorKeyNotFound, err)
+		assert.False(t, c.Has("beer"))
+	})
+
+	t.Run("Writes through to both caches", func(t *testing.T) {
+		fast := NewMemoryCache(0, 0)
+		slow := NewMemoryCache(0, 0)
+		c := NewTieredCache(fast, slow, TieredCacheConfig{Policy: WriteThrough})
+
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(c.SetMetadata("beer", &Metadata{ETag: "42"}))
+
+		assert.True(t, fast.Has("beer"))
+		assert.Equal(t, "lager", gotils.ResultOrPanic(slow.Get("beer")))
+		assert.Equal(t, "42", gotils.ResultOrPanic(slow.(IMetadataCache).GetMetadata("beer")).ETag)
+	})
+
+	t.Run("Returns error if writing through fails", func(t *testing.T) {
+		fast := NewMemoryCache(0, 0)
+		slow := &MockCache{Set_: func(key, val string) error { return errors.New("UNEXPECTED_ERROR") }}
+		c := NewTieredCache(fast, slow, TieredCacheConfig{Policy: WriteThrough})
+
+		assert.EqualError(t, c.Set("beer", "lager"), "UNEXPECTED_ERROR")
+		assert.False(t, fast.Has("beer"))
+	})
+
+	for name, policy := range map[string]WritePolicy{"write-through": WriteThrough, "write-back": WriteBack} {
+		t.Run("Does not return stale value if value is too large for the fast cache with "+name, func(t *testing.T) {
+			fast := NewMemoryCache(0, 20)
+			slow := NewMemoryCache(0, 0)
+			c := NewTieredCache(fast, slow, TieredCacheConfig{Policy: policy})
+			large := strings.Repeat("a", 100)
+			gotils.NilOrPanic(c.Set("k", "old"))
+
+			err := c.Set("k", large)
+
+			assert.Nil(t, err)
+			assert.Equal(t, large, gotils.ResultOrPanic(c.Get("k")))
+			assert.False(t, fast.Has("k"))
+		})
+	}
+
+	t.Run("Deletes from the fast cache if writing to it fails", func(t *testing.T) {
+		deleted := []string{}
+		fast := &MockCache{
+			Set_:    func(key, val string) error { return errors.New("UNEXPECTED_ERROR") },
+			Delete_: func(key string) error { deleted = append(deleted, key); return nil },
+		}
+		slow := NewMemoryCache(0, 0)
+		c := NewTieredCache(fast, slow, TieredCacheConfig{Policy: WriteThrough})
+
+		err := c.Set("beer", "lager")
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"beer"}, deleted)
+		assert.Equal(t, "lager", gotils.ResultOrPanic(slow.Get("beer")))
+	})
+
+	t.Run("Writes back to the slow cache on flush", func(t *testing.T) {
+		fast := NewMemoryCache(1, 0)
+		slow := NewMemoryCache(0, 0)
+		c := NewTieredCache(fast, slow, TieredCacheConfig{Policy: WriteBack})
+
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+		gotils.NilOrPanic(c.SetMetadata("beer", &Metadata{ETag: "42"}))
+		gotils.NilOrPanic(c.Set("wine", "red"))
+
+		assert.False(t, slow.Has("beer"))
+		assert.True(t, c.Has("beer"))
+		assert.Equal(t, "lager", gotils.ResultOrPanic(c.Get("beer")))
+		assert.Equal(t, "42", gotils.ResultOrPanic(c.GetMetadata("beer")).ETag)
+
+		gotils.NilOrPanic(c.Flush())
+
+		assert.Equal(t, "lager", gotils.ResultOrPanic(slow.Get("beer")))
+		assert.Equal(t, "red", gotils.ResultOrPanic(slow.Get("wine")))
+		assert.Equal(t, "42", gotils.ResultOrPanic(slow.(IMetadataCache).GetMetadata("beer")).ETag)
+	})
+
+	t.Run("Flushes if too many writes are pending", func(t *testing.T) {
+		slow := NewMemoryCache(0, 0)
+		c := NewTieredCache(NewMemoryCache(0, 0), slow, TieredCacheConfig{Policy: WriteBack, MaxPending: 100})
+
+		for i := 0; i < 100; i++ {
+			gotils.NilOrPanic(c.Set(string(rune('a'+i)), "value"))
+		}
+
+		assert.True(t, slow.Has("a"))
+	})
+
+	t.Run("Flushes every write if at most one write may be pending", func(t *testing.T) {
+		slow := NewMemoryCache(0, 0)
+		c := NewTieredCache(NewMemoryCache(0, 0), slow, TieredCacheConfig{Policy: WriteBack, MaxPending: 1})
+
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		assert.True(t, slow.Has("beer"))
+	})
+
+	t.Run("Allows 100 pending writes by default", func(t *testing.T) {
+		config := &TieredCacheConfig{}
+
+		config.validate()
+
+		assert.Equal(t, 100, config.MaxPending)
+	})
+
+	t.Run("Keeps pending writes if flush fails", func(t *testing.T) {
+		fail := true
+		slow := NewMemoryCache(0, 0)
+		c := NewTieredCache(NewMemoryCache(0, 0), &MockCache{
+			Set_: func(key, val string) error {
+				if fail {
+					return errors.New("UNEXPECTED_ERROR")
+				}
+				return slow.Set(key, val)
+			},
+		}, TieredCacheConfig{Policy: WriteBack})
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		assert.EqualError(t, c.Flush(), "UNEXPECTED_ERROR")
+		fail = false
+		gotils.NilOrPanic(c.Flush())
+
+		assert.Equal(t, "lager", gotils.ResultOrPanic(slow.Get("beer")))
+	})
+
+	t.Run("Returns keys of all tiers", func(t *testing.T) {
+		fast := NewMemoryCache(0, 0)
+		slow := NewMemoryCache(0, 0)
+		c := NewTieredCache(fast, slow, TieredCacheConfig{Policy: WriteBack})
+		gotils.NilOrPanic(fast.Set("beer", "lager"))
+		gotils.NilOrPanic(slow.Set("wine", "red"))
+		gotils.NilOrPanic(c.Set("cider", "dry"))
+
+		keys, err := c.Keys()
+
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"beer", "wine", "cider"}, keys)
+	})
+
+	t.Run("Returns error if listing keys fails", func(t *testing.T) {
+		c := NewTieredCache(NewMemoryCache(0, 0), NewFileCache("/var/this_directory_does_not_exists"), TieredCacheConfig{})
+
+		_, err := c.Keys()
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Returns error if a tier is not iterable", func(t *testing.T) {
+		c := NewTieredCache(NewMemoryCache(0, 0), &MockCache{}, TieredCacheConfig{})
+
+		_, err := c.Keys()
+
+		assert.Equal(t, ErrorNotIterable, err)
+	})
+
+	t.Run("Deletes from all tiers", func(t *testing.T) {
+		fast := NewMemoryCache(0, 0)
+		slow := NewMemoryCache(0, 0)
+		c := NewTieredCache(fast, slow, TieredCacheConfig{})
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		assert.Nil(t, c.Delete("beer"))
+		assert.False(t, fast.Has("beer"))
+		assert.False(t, slow.Has("beer"))
+		assert.Equal(t, ErrorKeyNotFound, c.Delete("beer"))
+	})
+
+	t.Run("Deletes pending write", func(t *testing.T) {
+		c := NewTieredCache(NewMemoryCache(0, 0), NewMemoryCache(0, 0), TieredCacheConfig{Policy: WriteBack})
+		gotils.NilOrPanic(c.Set("beer", "lager"))
+
+		assert.Nil(t, c.Delete("beer"))
+		assert.False(t, c.Has("beer"))
+	})
+}
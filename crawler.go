@@ -2,7 +2,12 @@ package grawler
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/DAtek/grawler/cache"
@@ -11,22 +16,56 @@ import (
 	"github.com/DAtek/gotils"
 )
 
+const ErrorNotCached = gotils.Error("NOT_CACHED")
+
 type ICrawler[T any] interface {
 	Crawl(startingUrl string) <-chan *T
 	Stop()
 	WaitStopped()
 }
 
+type IReanalyzingCrawler[T any] interface {
+	ICrawler[T]
+	Reanalyze(startingUrl string) <-chan *T
+	ReanalyzeAll() <-chan *T
+	Err() error
+}
+
+type IStatsCrawler[T any] interface {
+	IReanalyzingCrawler[T]
+	Stats() CrawlerStats
+}
+
 type MockCrawler[T any] struct {
-	Crawl_       func(startingUrl string) <-chan *T
-	Stop_        func()
-	WaitStopped_ func()
+	Crawl_        func(startingUrl string) <-chan *T
+	Reanalyze_    func(startingUrl string) <-chan *T
+	ReanalyzeAll_ func() <-chan *T
+	Err_          func() error
+	Stats_        func() CrawlerStats
+	Stop_         func()
+	WaitStopped_  func()
 }
 
 func (c MockCrawler[T]) Crawl(startingUrl string) <-chan *T {
 	return c.Crawl_(startingUrl)
 }
 
+func (c MockCrawler[T]) Reanalyze(startingUrl string) <-chan *T {
+	return c.Reanalyze_(startingUrl)
+}
+
+func (c MockCrawler[T]) ReanalyzeAll() <-chan *T {
+	return c.ReanalyzeAll_()
+}
+
+func (c MockCrawler[T]) Err() error {
+	return c.Err_()
+}
+
+func (c MockCrawler[T]) Stats() CrawlerStats {
+	return c.Stats_()
+}
+
 func (c MockCrawler[T]) Stop() {
 	c.Stop_()
 }
@@ -35,20 +74,82 @@ func (c MockCrawler[T]) WaitStopped() {
 	c.WaitStopped_()
 }
 
+var metadataHeaders = []string{"Etag", "Last-Modified", "Content-Type", "Cache-Control", "Expires", "Date"}
+
+type loadPageFunc func(remainingUrlCh chan *page_loader.Request, downloadedUrlChan chan *page_loader.Request, i int) bool
+
 type CrawlerConfig struct {
 	PageLoaders         int
 	PageAnalyzers       int
 	RemainingUrlChSize  int
 	DownloadedUrlChSize int
 	ResultChSize        int
+	RevalidateCache     bool
+	CacheExpiry         *cache.ExpiryPolicy
+	DeferDelay          time.Duration
+	SkipDuplicates      bool
+	NearDuplicates      *NearDuplicateConfig
+}
+
+type CrawlerStats struct {
+	Downloaded     int64
+	Analyzed       int64
+	Models         int64
+	Duplicates     int64
+	NearDuplicates int64
+	CacheHits      int64
+	CacheMisses    int64
+	CacheErrors    int64
+	Cache          cache.CacheStats
+}
+
+type crawlerStats struct {
+	downloaded     atomic.Int64
+	analyzed       atomic.Int64
+	models         atomic.Int64
+	duplicates     atomic.Int64
+	nearDuplicates atomic.Int64
+	cacheHits      atomic.Int64
+	cacheMisses    atomic.Int64
+	cacheErrors    atomic.Int64
+}
+
+type crawlerFailure struct {
+	err   error
+	mutex sync.Mutex
+}
+
+func (f *crawlerFailure) set(err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.err == nil {
+		f.err = err
+	}
+}
+
+func (f *crawlerFailure) get() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.err
 }
 
 func (c *CrawlerConfig) validate() {
-	c.PageLoaders = maxInt(c.PageLoaders, 1)
-	c.PageAnalyzers = maxInt(c.PageAnalyzers, 1)
-	c.RemainingUrlChSize = maxInt(c.RemainingUrlChSize, 10)
-	c.DownloadedUrlChSize = maxInt(c.DownloadedUrlChSize, 10)
-	c.ResultChSize = maxInt(c.ResultChSize, 10)
+	c.PageLoaders = max(c.PageLoaders, 1)
+	c.PageAnalyzers = max(c.PageAnalyzers, 1)
+	c.RemainingUrlChSize = max(c.RemainingUrlChSize, 10)
+	c.DownloadedUrlChSize = max(c.DownloadedUrlChSize, 10)
+	c.ResultChSize = max(c.ResultChSize, 10)
+
+	if c.DeferDelay <= 0 {
+		c.DeferDelay = 1 * time.Second
+	}
+
+	if c.NearDuplicates != nil {
+		nearDuplicates := *c.NearDuplicates
+		nearDuplicates.validate()
+		c.NearDuplicates = &nearDuplicates
+	}
 }
 
 func NewCrawler[T any](
@@ -58,17 +159,27 @@ func NewCrawler[T any](
 	logger *gotils.Logger,
 	baseUrl string,
 	config CrawlerConfig,
-) ICrawler[T] {
+) IStatsCrawler[T] {
 	config.validate()
 	ctx, cancel := context.WithCancel(context.Background())
 	stopCh := ctx.Done()
 
+	var nearDuplicateDetector *nearDuplicateDetector
+	if config.NearDuplicates != nil {
+		nearDuplicateDetector = newNearDuplicateDetector(config.NearDuplicates.MaxDistance, config.NearDuplicates.MinFeatures)
+	}
+
 	return &crawler[T]{
 		cache:          cache,
 		createAnalyzer: createAnalyzer,
 		pageLoader:     pageLoader,
 		logger:         logger,
 		urlRegistry:    newStringRegistry(),
+		bodyRegistry:   newStringRegistry(),
+		stats:          &crawlerStats{},
+		deferred:       newDeferredQueue(),
+		failure:        &crawlerFailure{},
+		nearDuplicates: nearDuplicateDetector,
 		baseUrl:        baseUrl,
 		stopCh:         stopCh,
 		wg:             &sync.WaitGroup{},
@@ -82,6 +193,11 @@ type crawler[T any] struct {
 	createAnalyzer NewAnalyzer[T]
 	pageLoader     page_loader.IPageLoader
 	urlRegistry    *stringRegistry
+	bodyRegistry   *stringRegistry
+	stats          *crawlerStats
+	deferred       *deferredQueue
+	failure        *crawlerFailure
+	nearDuplicates *nearDuplicateDetector
 	baseUrl        string
 	logger         *gotils.Logger
 	wg             *sync.WaitGroup
@@ -91,12 +207,94 @@ type crawler[T any] struct {
 }
 
 func (c crawler[T]) Crawl(startingUrl string) <-chan *T {
+	resultCh, remainingUrlCh, _ := c.start(c.LoadPage)
+	c.urlRegistry.add(startingUrl)
+	remainingUrlCh <- page_loader.NewRequest(startingUrl)
+	return resultCh
+}
+
+func (c crawler[T]) Reanalyze(startingUrl string) <-chan *T {
+	resultCh, remainingUrlCh, _ := c.start(c.LoadCachedPage)
+	c.urlRegistry.add(startingUrl)
+	remainingUrlCh <- page_loader.NewRequest(startingUrl)
+
+	go func() {
+		c.wg.Wait()
+		close(resultCh)
+	}()
+
+	return resultCh
+}
+
+func (c crawler[T]) ReanalyzeAll() <-chan *T {
+	resultCh := make(chan *T, c.config.ResultChSize)
+
+	iterableCache, ok := c.cache.(cache.IIterableCache)
+	if !ok {
+		c.logger.Error("reanalyzeAll | Cache doesn't support listing keys")
+		c.failure.set(cache.ErrorNotIterable)
+		close(resultCh)
+		return resultCh
+	}
+
+	keys, err := iterableCache.Keys()
+	if err != nil {
+		c.logger.Error("reanalyzeAll | Error listing cache keys. Error: %s", err)
+		c.failure.set(err)
+		close(resultCh)
+		return resultCh
+	}
+
+	requestCh := make(chan *page_loader.Request)
+	workers := &sync.WaitGroup{}
+	workers.Add(c.config.PageAnalyzers)
+
+	for i := 0; i < c.config.PageAnalyzers; i++ {
+		go func(i int) {
+			defer func() {
+				c.logger.Debug("Stopping page analyzer %d", i)
+				workers.Done()
+			}()
+
+			for req := range requestCh {
+				c.analyze(req, nil, resultCh, i)
+			}
+		}(i)
+	}
+
+	c.wg.Add(1)
+	go func() {
+		defer func() {
+			c.logger.Debug("Stopping cache iterator")
+			close(requestCh)
+			workers.Wait()
+			close(resultCh)
+			c.wg.Done()
+		}()
+
+		for _, key := range keys {
+			req, ok := c.restoreRequest(key)
+			if !ok {
+				c.logger.Warning("reanalyzeAll | Skipping %s, the request can't be restored", key)
+				continue
+			}
+
+			select {
+			case <-c.stopCh:
+				return
+			case requestCh <- req:
+			}
+		}
+	}()
+
+	return resultCh
+}
+
+func (c crawler[T]) start(loadPage loadPageFunc) (chan *T, chan *page_loader.Request, chan *page_loader.Request) {
 	resultCh := make(chan *T, c.config.ResultChSize)
-	remainingUrlCh := make(chan string, c.config.RemainingUrlChSize)
-	downloadedUrlCh := make(chan string, c.config.DownloadedUrlChSize)
+	remainingUrlCh := make(chan *page_loader.Request, c.config.RemainingUrlChSize)
+	downloadedUrlCh := make(chan *page_loader.Request, c.config.DownloadedUrlChSize)
 	c.wg.Add(c.totalWorkers())
-	c.urlRegistry.add(startingUrl)
-	remainingUrlCh <- startingUrl
 
 	pageLoader := func(i int) {
 		defer func() {
@@ -104,7 +302,7 @@ func (c crawler[T]) Crawl(startingUrl string) <-chan *T {
 			c.wg.Done()
 		}()
 
-		for c.LoadPage(remainingUrlCh, downloadedUrlCh, i) {
+		for loadPage(remainingUrlCh, downloadedUrlCh, i) {
 		}
 	}
 
@@ -148,7 +346,30 @@ func (c crawler[T]) Crawl(startingUrl string) <-chan *T {
 		}
 	}()
 
-	return resultCh
+	return resultCh, remainingUrlCh, downloadedUrlCh
+}
+
+func (c crawler[T]) Stats() CrawlerStats {
+	stats := CrawlerStats{
+		Downloaded:     c.stats.downloaded.Load(),
+		Analyzed:       c.stats.analyzed.Load(),
+		Models:         c.stats.models.Load(),
+		Duplicates:     c.stats.duplicates.Load(),
+		NearDuplicates: c.stats.nearDuplicates.Load(),
+		CacheHits:      c.stats.cacheHits.Load(),
+		CacheMisses:    c.stats.cacheMisses.Load(),
+		CacheErrors:    c.stats.cacheErrors.Load(),
+	}
+
+	if statsCache, ok := c.cache.(cache.IStatsCache); ok {
+		stats.Cache = statsCache.Stats()
+	}
+
+	return stats
+}
+
+func (c crawler[T]) Err() error {
+	return c.failure.get()
 }
 
 func (c crawler[T]) Stop() {
@@ -163,74 +384,340 @@ func (c crawler[T]) totalWorkers() int {
 	return c.config.PageLoaders + c.config.PageAnalyzers + 1
 }
 
-func (c crawler[T]) LoadPage(remainingUrlCh chan string, downloadedUrlChan chan string, i int) bool {
+func (c crawler[T]) LoadPage(remainingUrlCh chan *page_loader.Request, downloadedUrlChan chan *page_loader.Request, i int) bool {
 	select {
 	case <-c.stopCh:
 		return false
-	case newUrl := <-remainingUrlCh:
-		if c.cache.Has(newUrl) {
-			c.logger.Debug("loadPage(%d) | Found in cache %s", i, newUrl)
-			downloadedUrlChan <- newUrl
+	case req := <-remainingUrlCh:
+		key := req.Fingerprint()
+
+		var meta *cache.Metadata
+		cached := c.cache.Has(key)
+		if cached {
+			meta = c.getMetadata(key)
+			expired := c.config.CacheExpiry.IsExpired(key, meta, time.Now())
+
+			if !expired && !c.config.RevalidateCache {
+				c.logger.Debug("loadPage(%d) | Found in cache %s", i, key)
+				c.stats.cacheHits.Add(1)
+				downloadedUrlChan <- req
+				return true
+			}
+
+			if expired {
+				c.logger.Debug("loadPage(%d) | Cache entry expired %s", i, key)
+			}
+
+			if c.config.RevalidateCache {
+				addValidators(req, meta)
+			}
+		}
+
+		revalidating := meta != nil && c.config.RevalidateCache
+
+		host := page_loader.GetHost(req.Url)
+		resp, err := c.pageLoader.LoadPage(req)
+		if err == nil && resp == nil {
+			err = page_loader.ErrorEmptyResponse
+		}
+		if errors.Is(err, page_loader.ErrorCircuitOpen) {
+			c.logger.Debug("loadPage(%d) | Host unavailable, deferring %s", i, key)
+			if released, isNew := c.deferred.add(host, req); isNew {
+				c.wg.Add(1)
+				go c.drainDeferred(host, released, remainingUrlCh)
+			}
+			return true
+		}
+
+		c.deferred.release(host)
+		if !revalidating {
+			c.stats.cacheMisses.Add(1)
+		}
+
+		if err != nil && cached {
+			c.logger.Warning("loadPage(%d) | Error refetching '%s', using stale cache entry. Error: %s", i, key, err)
+			downloadedUrlChan <- req
 			return true
 		}
 
-		c.logger.Info("loadPage(%d) | Downloading from %s", i, newUrl)
-		page, err := c.pageLoader.LoadPage(newUrl)
 		if err != nil {
-			c.logger.Error("loadPage(%d) | Error loading from '%s' Error: %s", i, newUrl, err)
+			c.logger.Error("loadPage(%d) | Error loading from '%s' Error: %s", i, key, err)
+			return true
+		}
+
+		c.logger.Info("loadPage(%d) | Downloaded from %s", i, key)
+		if resp.StatusCode == http.StatusNotModified {
+			c.logger.Debug("loadPage(%d) | Not modified, using cache %s", i, key)
+			c.stats.cacheHits.Add(1)
+			if err := c.touchMetadata(key, meta); err != nil {
+				c.logger.Error("loadPage(%d) | Error saving metadata to cache. '%s' Error: %s", i, key, err)
+				c.stats.cacheErrors.Add(1)
+			}
+			downloadedUrlChan <- req
 			return true
 		}
 
-		if err := c.cache.Set(newUrl, page); err != nil {
-			c.logger.Error("loadPage(%d) | Error saving to cache. '%s' Error: %s", i, newUrl, err)
+		if revalidating {
+			c.stats.cacheMisses.Add(1)
+		}
+
+		c.stats.downloaded.Add(1)
+		if err := c.cache.Set(key, resp.Body); err != nil {
+			c.logger.Error("loadPage(%d) | Error saving to cache. '%s' Error: %s", i, key, err)
+			c.stats.cacheErrors.Add(1)
 			return true
 		}
-		downloadedUrlChan <- newUrl
+
+		if err := c.saveMetadata(key, req, resp); err != nil {
+			c.logger.Error("loadPage(%d) | Error saving metadata to cache. '%s' Error: %s", i, key, err)
+			c.stats.cacheErrors.Add(1)
+		}
+		downloadedUrlChan <- req
 		return true
 	}
 
 }
 
-func (c crawler[T]) AnalyzePage(downloadedUrlCh, remainingUrlCh chan string, resultCh chan *T, i int) bool {
+func (c crawler[T]) LoadCachedPage(remainingUrlCh chan *page_loader.Request, downloadedUrlChan chan *page_loader.Request, i int) bool {
 	select {
 	case <-c.stopCh:
 		return false
-	case newUrl := <-downloadedUrlCh:
-		page, err := c.cache.Get(newUrl)
+	case req := <-remainingUrlCh:
+		key := req.Fingerprint()
+
+		if !c.cache.Has(key) {
+			c.logger.Error("loadCachedPage(%d) | Stopping, page is missing from cache '%s' Error: %s", i, key, ErrorNotCached)
+			c.failure.set(fmt.Errorf("%w: %s", ErrorNotCached, key))
+			c.cancel()
+			return false
+		}
 
-		if err != nil {
-			c.logger.Error("analyzePage(%d) | Error loading from '%s' Error: %s", i, newUrl, err)
-			return true
+		c.logger.Debug("loadCachedPage(%d) | Found in cache %s", i, key)
+		downloadedUrlChan <- req
+		return true
+	}
+}
+
+func (c crawler[T]) drainDeferred(host string, released <-chan struct{}, remainingUrlCh chan *page_loader.Request) {
+	defer func() {
+		c.logger.Debug("Stopping deferred queue of %s", host)
+		c.wg.Done()
+	}()
+
+	for {
+		all := false
+		select {
+		case <-c.stopCh:
+			return
+		case <-released:
+			all = true
+		case <-time.After(c.config.DeferDelay):
 		}
 
-		c.logger.Debug("analyzePage(%d) | Analyzing page %s", i, newUrl)
-		analyzer, err := c.createAnalyzer(&page, &newUrl)
-		if err != nil {
-			c.logger.Error("analyzePage(%d) | Failed to create the analyzer. URL: %s Error: %s", i, newUrl, err)
-			return true
+		requests, done := c.deferred.take(host, all)
+		for _, req := range requests {
+			select {
+			case <-c.stopCh:
+				return
+			case remainingUrlCh <- req:
+			}
 		}
 
-		if model := analyzer.GetModel(); model != nil {
-			c.logger.Info("analyzePage(%d) | Collected model for %s", i, newUrl)
-			resultCh <- model
+		if done {
+			return
 		}
+	}
+}
 
-		for _, newUrl := range c.urlRegistry.getNew(analyzer.GetUrls()) {
-			c.urlRegistry.add(newUrl)
-			if string(newUrl[0]) == "/" {
-				newUrl = joinPath(c.baseUrl, newUrl)
-			}
-			c.logger.Debug("Adding URL: %s", newUrl)
-			remainingUrlCh <- newUrl
+func (c crawler[T]) getMetadata(key string) *cache.Metadata {
+	metadataCache, ok := c.cache.(cache.IMetadataCache)
+	if !ok {
+		return nil
+	}
+
+	meta, err := metadataCache.GetMetadata(key)
+	if err != nil {
+		return nil
+	}
+
+	return meta
+}
+
+func (c crawler[T]) saveMetadata(key string, req *page_loader.Request, resp *page_loader.Response) error {
+	metadataCache, ok := c.cache.(cache.IMetadataCache)
+	if !ok {
+		return nil
+	}
+
+	header := http.Header{}
+	for _, name := range metadataHeaders {
+		if values := resp.Header.Values(name); len(values) > 0 {
+			header[name] = append([]string(nil), values...)
+		}
+	}
+
+	return metadataCache.SetMetadata(key, &cache.Metadata{
+		FetchedAt:    time.Now(),
+		StatusCode:   resp.StatusCode,
+		Header:       header,
+		ETag:         header.Get("ETag"),
+		LastModified: header.Get("Last-Modified"),
+		Method:       req.Method,
+		Url:          req.Url,
+		RequestBody:  req.Body,
+	})
+}
+
+func (c crawler[T]) restoreRequest(key string) (*page_loader.Request, bool) {
+	if meta := c.getMetadata(key); meta != nil && meta.Url != "" {
+		req := &page_loader.Request{Method: meta.Method, Url: meta.Url, Header: http.Header{}, Body: meta.RequestBody}
+		if req.Fingerprint() == key {
+			return req, true
 		}
+	}
+
+	u, err := url.Parse(key)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil, false
+	}
+
+	return page_loader.NewRequest(key), true
+}
+
+func (c crawler[T]) touchMetadata(key string, meta *cache.Metadata) error {
+	metadataCache, ok := c.cache.(cache.IMetadataCache)
+	if !ok || meta == nil {
+		return nil
+	}
+
+	meta.FetchedAt = time.Now()
+	return metadataCache.SetMetadata(key, meta)
+}
+
+func addValidators(req *page_loader.Request, meta *cache.Metadata) {
+	if meta == nil {
+		return
+	}
+
+	if req.Header == nil {
+		req.Header = http.Header{}
+	}
+
+	if meta.ETag != "" {
+		req.Header.Set("If-None-Match", meta.ETag)
+	}
+
+	if meta.LastModified != "" {
+		req.Header.Set("If-Modified-Since", meta.LastModified)
+	}
+}
+
+func (c crawler[T]) AnalyzePage(downloadedUrlCh, remainingUrlCh chan *page_loader.Request, resultCh chan *T, i int) bool {
+	select {
+	case <-c.stopCh:
+		return false
+	case req := <-downloadedUrlCh:
+		c.analyze(req, remainingUrlCh, resultCh, i)
 		return true
 	}
 }
 
-func maxInt(x, y int) int {
-	if x >= y {
-		return x
+func (c crawler[T]) analyze(req *page_loader.Request, remainingUrlCh chan *page_loader.Request, resultCh chan *T, i int) {
+	key := req.Fingerprint()
+	page, err := c.cache.Get(key)
+
+	if err != nil {
+		c.logger.Error("analyzePage(%d) | Error loading from '%s' Error: %s", i, key, err)
+		if cache.IsNotFound(err) {
+			c.stats.cacheMisses.Add(1)
+		} else {
+			c.stats.cacheErrors.Add(1)
+		}
+		return
+	}
+
+	if c.config.SkipDuplicates && !c.bodyRegistry.add(c.getBodyHash(key, page)) {
+		c.logger.Debug("analyzePage(%d) | Skipping duplicate page %s", i, key)
+		c.stats.duplicates.Add(1)
+		return
+	}
+
+	var skip NearDuplicateAction
+	if c.nearDuplicates != nil && c.nearDuplicates.check(page) {
+		c.logger.Debug("analyzePage(%d) | Found near-duplicate page %s", i, key)
+		c.stats.nearDuplicates.Add(1)
+		skip = c.config.NearDuplicates.Action
+	}
+
+	if skip == SkipNearDuplicateModels|SkipNearDuplicateUrls {
+		return
+	}
+
+	c.logger.Debug("analyzePage(%d) | Analyzing page %s", i, key)
+	sourceUrl := req.Url
+	analyzer, err := c.createAnalyzer(&page, &sourceUrl)
+	if err != nil {
+		c.logger.Error("analyzePage(%d) | Failed to create the analyzer. URL: %s Error: %s", i, key, err)
+		return
+	}
+
+	c.stats.analyzed.Add(1)
+	if model := analyzer.GetModel(); model != nil && skip&SkipNearDuplicateModels == 0 {
+		c.logger.Info("analyzePage(%d) | Collected model for %s", i, key)
+		c.stats.models.Add(1)
+		select {
+		case <-c.stopCh:
+			return
+		case resultCh <- model:
+		}
+	}
+
+	if remainingUrlCh == nil || skip&SkipNearDuplicateUrls != 0 {
+		return
+	}
+
+	newRequests := []*page_loader.Request{}
+	for _, newUrl := range analyzer.GetUrls() {
+		newRequests = append(newRequests, page_loader.NewRequest(newUrl))
+	}
+
+	if requestAnalyzer, ok := analyzer.(IRequestAnalyzer); ok {
+		newRequests = append(newRequests, requestAnalyzer.GetRequests()...)
+	}
+
+	for _, newReq := range newRequests {
+		if newReq.Url == "" {
+			continue
+		}
+
+		newReq.Url = c.absoluteUrl(newReq.Url)
+		fingerprint := newReq.Fingerprint()
+		if !c.urlRegistry.add(fingerprint) {
+			continue
+		}
+
+		if newReq.Referer == "" {
+			newReq.Referer = sourceUrl
+		}
+		c.logger.Debug("Adding request: %s", fingerprint)
+		remainingUrlCh <- newReq
+	}
+}
+
+func (c crawler[T]) getBodyHash(key, page string) string {
+	if contentAddressedCache, ok := c.cache.(cache.IContentAddressedCache); ok {
+		if hash, err := contentAddressedCache.GetHash(key); err == nil {
+			return hash
+		}
+	}
+
+	return cache.HashBody(page)
+}
+
+func (c crawler[T]) absoluteUrl(u string) string {
+	if len(u) > 0 && u[0] == '/' {
+		return joinPath(c.baseUrl, u)
 	}
 
-	return y
+	return u
 }
@@ -3,8 +3,12 @@ package grawler
 import (
 	"bytes"
 	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/DAtek/grawler/cache"
 	"github.com/DAtek/grawler/page_loader"
@@ -66,8 +70,8 @@ func TestStartCrawling(t *testing.T) {
 			},
 			createAnalyzer,
 			&page_loader.MockPageLoader{
-				LoadPage_: func(url string) (string, error) {
-					return "", nil
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					return &page_loader.Response{StatusCode: http.StatusOK}, nil
 				},
 			},
 			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
@@ -132,8 +136,8 @@ func TestStartCrawling(t *testing.T) {
 			},
 			createAnalyzer,
 			&page_loader.MockPageLoader{
-				LoadPage_: func(url string) (string, error) {
-					return "", nil
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					return &page_loader.Response{StatusCode: http.StatusOK}, nil
 				},
 			},
 			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
@@ -141,13 +145,14 @@ func TestStartCrawling(t *testing.T) {
 			CrawlerConfig{},
 		).(*crawler[ExapleModel])
 
-		remainingUrlCh := make(chan string, 1)
-		remainingUrlCh <- url
-		downloadedUrlCh := make(chan string, 1)
+		remainingUrlCh := make(chan *page_loader.Request, 1)
+		remainingUrlCh <- page_loader.NewRequest(url)
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
 
 		assert.True(t, crawler_.LoadPage(remainingUrlCh, downloadedUrlCh, 1))
 
-		assert.Equal(t, url, <-downloadedUrlCh)
+		assert.Equal(t, url, (<-downloadedUrlCh).Url)
+		assert.Equal(t, int64(1), crawler_.Stats().CacheHits)
 	})
 
 	t.Run("Test LoadPage logs error if downloading fails", func(t *testing.T) {
@@ -172,8 +177,8 @@ func TestStartCrawling(t *testing.T) {
 			},
 			createAnalyzer,
 			&page_loader.MockPageLoader{
-				LoadPage_: func(url string) (string, error) {
-					return "", err
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					return nil, err
 				},
 			},
 			gotils.NewLogger(gotils.LogLevelInfo, outBuf, &bytes.Buffer{}),
@@ -181,14 +186,48 @@ func TestStartCrawling(t *testing.T) {
 			CrawlerConfig{},
 		).(*crawler[ExapleModel])
 
-		remainingUrlCh := make(chan string, 1)
-		remainingUrlCh <- "asd"
-		downloadedUrlCh := make(chan string, 1)
+		remainingUrlCh := make(chan *page_loader.Request, 1)
+		remainingUrlCh <- page_loader.NewRequest("asd")
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
 
 		assert.True(t, crawler_.LoadPage(remainingUrlCh, downloadedUrlCh, 1))
 		assert.True(t, strings.Contains(outBuf.String(), err.Error()))
 	})
 
+	t.Run("Test LoadPage logs error if page loader returns no response", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		var createAnalyzer NewAnalyzer[ExapleModel] = func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			return &MockAnalyzer{}, nil
+		}
+
+		outBuf := &bytes.Buffer{}
+		crawler_ := NewCrawler(
+			&cache.MockCache{
+				Has_: func(key string) bool { return false },
+			},
+			createAnalyzer,
+			&page_loader.MockPageLoader{
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					return nil, nil
+				},
+			},
+			gotils.NewLogger(gotils.LogLevelInfo, outBuf, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{},
+		).(*crawler[ExapleModel])
+
+		remainingUrlCh := make(chan *page_loader.Request, 1)
+		remainingUrlCh <- page_loader.NewRequest("asd")
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
+
+		assert.True(t, crawler_.LoadPage(remainingUrlCh, downloadedUrlCh, 1))
+		assert.Contains(t, outBuf.String(), page_loader.ErrorEmptyResponse.Error())
+		assert.Equal(t, 0, len(downloadedUrlCh))
+	})
+
 	t.Run("Test LoadPage logs error if writing to cache fails", func(t *testing.T) {
 		timeout := gotils.NewTimeoutMs(100)
 		go func() { panic(<-timeout.ErrorCh) }()
@@ -214,8 +253,8 @@ func TestStartCrawling(t *testing.T) {
 			},
 			createAnalyzer,
 			&page_loader.MockPageLoader{
-				LoadPage_: func(url string) (string, error) {
-					return "", nil
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					return &page_loader.Response{StatusCode: http.StatusOK}, nil
 				},
 			},
 			gotils.NewLogger(gotils.LogLevelInfo, outBuf, &bytes.Buffer{}),
@@ -223,14 +262,333 @@ func TestStartCrawling(t *testing.T) {
 			CrawlerConfig{},
 		).(*crawler[ExapleModel])
 
-		remainingUrlCh := make(chan string, 1)
-		remainingUrlCh <- "asd"
-		downloadedUrlCh := make(chan string, 1)
+		remainingUrlCh := make(chan *page_loader.Request, 1)
+		remainingUrlCh <- page_loader.NewRequest("asd")
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
 
 		assert.True(t, crawler_.LoadPage(remainingUrlCh, downloadedUrlCh, 1))
 		assert.True(t, strings.Contains(outBuf.String(), err.Error()))
 	})
 
+	t.Run("Test LoadPage revalidates cached page", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		var createAnalyzer NewAnalyzer[ExapleModel] = func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			return &MockAnalyzer{}, nil
+		}
+
+		etag := `"v1"`
+		pageSaved := false
+		var sentHeader http.Header
+		var savedMeta *cache.Metadata
+		crawler_ := NewCrawler(
+			&cache.MockMetadataCache{
+				MockCache: cache.MockCache{
+					Has_: func(key string) bool {
+						return true
+					},
+					Set_: func(key, val string) error {
+						pageSaved = true
+						return nil
+					},
+				},
+				GetMetadata_: func(key string) (*cache.Metadata, error) {
+					return &cache.Metadata{ETag: etag}, nil
+				},
+				SetMetadata_: func(key string, meta *cache.Metadata) error {
+					savedMeta = meta
+					return nil
+				},
+			},
+			createAnalyzer,
+			&page_loader.MockPageLoader{
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					sentHeader = req.Header
+					return &page_loader.Response{StatusCode: http.StatusNotModified}, nil
+				},
+			},
+			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{RevalidateCache: true},
+		).(*crawler[ExapleModel])
+
+		url := "asd"
+		remainingUrlCh := make(chan *page_loader.Request, 1)
+		remainingUrlCh <- page_loader.NewRequest(url)
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
+
+		assert.True(t, crawler_.LoadPage(remainingUrlCh, downloadedUrlCh, 1))
+		assert.Equal(t, url, (<-downloadedUrlCh).Url)
+		assert.Equal(t, etag, sentHeader.Get("If-None-Match"))
+		assert.False(t, pageSaved)
+		assert.Equal(t, etag, savedMeta.ETag)
+		assert.False(t, savedMeta.FetchedAt.IsZero())
+		assert.Equal(t, int64(1), crawler_.Stats().CacheHits)
+		assert.Equal(t, int64(0), crawler_.Stats().CacheMisses)
+	})
+
+	t.Run("Test LoadPage saves validators of downloaded page", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		var createAnalyzer NewAnalyzer[ExapleModel] = func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			return &MockAnalyzer{}, nil
+		}
+
+		etag := `"v1"`
+		lastModified := "Wed, 21 Oct 2015 07:28:00 GMT"
+		var savedMeta *cache.Metadata
+		crawler_ := NewCrawler(
+			&cache.MockMetadataCache{
+				MockCache: cache.MockCache{
+					Has_: func(key string) bool {
+						return false
+					},
+					Set_: func(key, val string) error {
+						return nil
+					},
+				},
+				SetMetadata_: func(key string, meta *cache.Metadata) error {
+					savedMeta = meta
+					return nil
+				},
+			},
+			createAnalyzer,
+			&page_loader.MockPageLoader{
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					header := http.Header{}
+					header.Set("ETag", etag)
+					header.Set("Last-Modified", lastModified)
+					header.Set("Content-Type", "text/html")
+					header.Set("Set-Cookie", "session=secret")
+					header.Set("Authorization", "Bearer secret")
+					return &page_loader.Response{StatusCode: http.StatusOK, Header: header}, nil
+				},
+			},
+			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{},
+		).(*crawler[ExapleModel])
+
+		remainingUrlCh := make(chan *page_loader.Request, 1)
+		remainingUrlCh <- page_loader.NewRequest("asd")
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
+
+		assert.True(t, crawler_.LoadPage(remainingUrlCh, downloadedUrlCh, 1))
+		assert.Equal(t, "asd", (<-downloadedUrlCh).Url)
+		assert.Equal(t, etag, savedMeta.ETag)
+		assert.Equal(t, lastModified, savedMeta.LastModified)
+		assert.Equal(t, http.StatusOK, savedMeta.StatusCode)
+		assert.Equal(t, etag, savedMeta.Header.Get("ETag"))
+		assert.Equal(t, "text/html", savedMeta.Header.Get("Content-Type"))
+		assert.Empty(t, savedMeta.Header.Get("Set-Cookie"))
+		assert.Empty(t, savedMeta.Header.Get("Authorization"))
+		assert.Equal(t, http.MethodGet, savedMeta.Method)
+		assert.Equal(t, "asd", savedMeta.Url)
+		assert.False(t, savedMeta.FetchedAt.IsZero())
+	})
+
+	t.Run("Test LoadPage refetches expired page", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		var createAnalyzer NewAnalyzer[ExapleModel] = func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			return &MockAnalyzer{}, nil
+		}
+
+		fetchedAt := map[string]time.Time{
+			"http://demo.example/news/1":  time.Now().Add(-time.Hour),
+			"http://demo.example/about":   time.Now().Add(-time.Hour),
+			"http://demo.example/archive": time.Now().Add(-time.Minute),
+		}
+		loadedUrls := []string{}
+		crawler_ := NewCrawler(
+			&cache.MockMetadataCache{
+				MockCache: cache.MockCache{
+					Has_: func(key string) bool {
+						return true
+					},
+					Set_: func(key, val string) error {
+						return nil
+					},
+				},
+				GetMetadata_: func(key string) (*cache.Metadata, error) {
+					return &cache.Metadata{FetchedAt: fetchedAt[key]}, nil
+				},
+				SetMetadata_: func(key string, meta *cache.Metadata) error {
+					return nil
+				},
+			},
+			createAnalyzer,
+			&page_loader.MockPageLoader{
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					loadedUrls = append(loadedUrls, req.Url)
+					return &page_loader.Response{StatusCode: http.StatusOK}, nil
+				},
+			},
+			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{
+				CacheExpiry: &cache.ExpiryPolicy{
+					TTL:   30 * time.Second,
+					Rules: []cache.TTLRule{{Pattern: regexp.MustCompile("/about"), TTL: 2 * time.Hour}},
+				},
+			},
+		).(*crawler[ExapleModel])
+
+		remainingUrlCh := make(chan *page_loader.Request, 3)
+		downloadedUrlCh := make(chan *page_loader.Request, 3)
+		for _, url := range []string{"http://demo.example/news/1", "http://demo.example/about", "http://demo.example/archive"} {
+			remainingUrlCh <- page_loader.NewRequest(url)
+			assert.True(t, crawler_.LoadPage(remainingUrlCh, downloadedUrlCh, 1))
+		}
+
+		assert.Equal(t, []string{"http://demo.example/news/1", "http://demo.example/archive"}, loadedUrls)
+		assert.Equal(t, 3, len(downloadedUrlCh))
+	})
+
+	t.Run("Test LoadPage uses stale page if refetching fails", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		var createAnalyzer NewAnalyzer[ExapleModel] = func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			return &MockAnalyzer{}, nil
+		}
+
+		pageSaved := false
+		outBuf := &bytes.Buffer{}
+		crawler_ := NewCrawler(
+			&cache.MockMetadataCache{
+				MockCache: cache.MockCache{
+					Has_: func(key string) bool {
+						return true
+					},
+					Set_: func(key, val string) error {
+						pageSaved = true
+						return nil
+					},
+				},
+				GetMetadata_: func(key string) (*cache.Metadata, error) {
+					return &cache.Metadata{FetchedAt: time.Now().Add(-time.Hour)}, nil
+				},
+			},
+			createAnalyzer,
+			&page_loader.MockPageLoader{
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					return nil, errors.New("UNEXPECTED_ERROR")
+				},
+			},
+			gotils.NewLogger(gotils.LogLevelInfo, outBuf, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{CacheExpiry: &cache.ExpiryPolicy{TTL: time.Minute}},
+		).(*crawler[ExapleModel])
+
+		remainingUrlCh := make(chan *page_loader.Request, 1)
+		remainingUrlCh <- page_loader.NewRequest("http://demo.example/news/1")
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
+
+		assert.True(t, crawler_.LoadPage(remainingUrlCh, downloadedUrlCh, 1))
+		assert.Equal(t, "http://demo.example/news/1", (<-downloadedUrlCh).Url)
+		assert.False(t, pageSaved)
+		assert.Contains(t, outBuf.String(), "using stale cache entry")
+	})
+
+	t.Run("Test LoadPage defers request if host is unavailable", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		var createAnalyzer NewAnalyzer[ExapleModel] = func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			return &MockAnalyzer{}, nil
+		}
+
+		crawler_ := NewCrawler(
+			&cache.MockCache{
+				Has_: func(key string) bool {
+					return false
+				},
+			},
+			createAnalyzer,
+			&page_loader.MockPageLoader{
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					return nil, page_loader.ErrorCircuitOpen
+				},
+			},
+			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{DeferDelay: 10 * time.Millisecond},
+		).(*crawler[ExapleModel])
+		defer crawler_.Stop()
+
+		req := page_loader.NewRequest("asd")
+		remainingUrlCh := make(chan *page_loader.Request, 1)
+		remainingUrlCh <- req
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
+
+		assert.True(t, crawler_.LoadPage(remainingUrlCh, downloadedUrlCh, 1))
+		assert.Equal(t, req, <-remainingUrlCh)
+		assert.Equal(t, 0, len(downloadedUrlCh))
+		assert.Equal(t, int64(0), crawler_.Stats().CacheMisses)
+	})
+
+	t.Run("Test LoadPage releases deferred requests once host is available", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		var createAnalyzer NewAnalyzer[ExapleModel] = func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			return &MockAnalyzer{}, nil
+		}
+
+		calls := 0
+		crawler_ := NewCrawler(
+			&cache.MockCache{
+				Has_: func(key string) bool {
+					return false
+				},
+				Set_: func(key, val string) error {
+					return nil
+				},
+			},
+			createAnalyzer,
+			&page_loader.MockPageLoader{
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					calls++
+					if calls <= 2 {
+						return nil, page_loader.ErrorCircuitOpen
+					}
+					return &page_loader.Response{StatusCode: http.StatusOK}, nil
+				},
+			},
+			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{DeferDelay: 1 * time.Hour},
+		).(*crawler[ExapleModel])
+
+		req1 := page_loader.NewRequest("http://demo.example/1")
+		req2 := page_loader.NewRequest("http://demo.example/2")
+		remainingUrlCh := make(chan *page_loader.Request, 3)
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
+		remainingUrlCh <- req1
+		remainingUrlCh <- req2
+		remainingUrlCh <- page_loader.NewRequest("http://demo.example/3")
+
+		for i := 0; i < 3; i++ {
+			assert.True(t, crawler_.LoadPage(remainingUrlCh, downloadedUrlCh, 1))
+		}
+
+		assert.Equal(t, req1, <-remainingUrlCh)
+		assert.Equal(t, req2, <-remainingUrlCh)
+		assert.Equal(t, 1, len(downloadedUrlCh))
+		crawler_.Stop()
+		crawler_.WaitStopped()
+	})
+
 	t.Run("Test AnalyzePage adds base URL to new url", func(t *testing.T) {
 		timeout := gotils.NewTimeoutMs(100)
 		go func() { panic(<-timeout.ErrorCh) }()
@@ -259,8 +617,8 @@ func TestStartCrawling(t *testing.T) {
 			},
 			createAnalyzer,
 			&page_loader.MockPageLoader{
-				LoadPage_: func(url string) (string, error) {
-					return "", nil
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					return &page_loader.Response{StatusCode: http.StatusOK}, nil
 				},
 			},
 			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
@@ -268,15 +626,157 @@ func TestStartCrawling(t *testing.T) {
 			CrawlerConfig{},
 		).(*crawler[ExapleModel])
 
-		remainingUrlCh := make(chan string, 1)
-		downloadedUrlCh := make(chan string, 1)
+		remainingUrlCh := make(chan *page_loader.Request, 1)
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
 
-		downloadedUrlCh <- "asd"
+		downloadedUrlCh <- page_loader.NewRequest("asd")
 		resultCh := make(chan *ExapleModel, 1)
 
 		assert.True(t, crawler_.AnalyzePage(downloadedUrlCh, remainingUrlCh, resultCh, 1))
 		remainingUrl := <-remainingUrlCh
-		assert.Equal(t, baseUrl+newUrl, remainingUrl)
+		assert.Equal(t, baseUrl+newUrl, remainingUrl.Url)
+		assert.Equal(t, "asd", remainingUrl.Referer)
+	})
+
+	t.Run("Test AnalyzePage adds new requests only once", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		analyzer := &MockRequestAnalyzer{
+			MockAnalyzer: MockAnalyzer{
+				GetModel_: func() *ExapleModel { return nil },
+				GetUrls_:  func() []string { return []string{} },
+			},
+			GetRequests_: func() []*page_loader.Request {
+				return []*page_loader.Request{
+					page_loader.NewPostRequest("/search", "application/json", `{"page":2}`),
+					page_loader.NewPostRequest("/search", "application/json", `{"page":2}`),
+				}
+			},
+		}
+
+		var createAnalyzer NewAnalyzer[ExapleModel] = func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			return analyzer, nil
+		}
+
+		baseUrl := "http://demo.example"
+		crawler_ := NewCrawler(
+			&cache.MockCache{
+				Get_: func(key string) (string, error) {
+					return "", nil
+				},
+			},
+			createAnalyzer,
+			&page_loader.MockPageLoader{},
+			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
+			baseUrl,
+			CrawlerConfig{},
+		).(*crawler[ExapleModel])
+
+		remainingUrlCh := make(chan *page_loader.Request, 2)
+		downloadedUrlCh := make(chan *page_loader.Request, 2)
+		resultCh := make(chan *ExapleModel, 1)
+
+		downloadedUrlCh <- page_loader.NewRequest("asd")
+		assert.True(t, crawler_.AnalyzePage(downloadedUrlCh, remainingUrlCh, resultCh, 1))
+		downloadedUrlCh <- page_loader.NewRequest("asd")
+		assert.True(t, crawler_.AnalyzePage(downloadedUrlCh, remainingUrlCh, resultCh, 1))
+
+		assert.Equal(t, 1, len(remainingUrlCh))
+		req := <-remainingUrlCh
+		assert.Equal(t, http.MethodPost, req.Method)
+		assert.Equal(t, baseUrl+"/search", req.Url)
+		assert.Equal(t, "asd", req.Referer)
+	})
+
+	t.Run("Test AnalyzePage deduplicates URLs and requests by absolute URL", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		baseUrl := "http://demo.example"
+		analyzer := &MockRequestAnalyzer{
+			MockAnalyzer: MockAnalyzer{
+				GetModel_: func() *ExapleModel { return nil },
+				GetUrls_:  func() []string { return []string{"", "/beers", baseUrl + "/beers"} },
+			},
+			GetRequests_: func() []*page_loader.Request {
+				return []*page_loader.Request{
+					page_loader.NewRequest("/beers"),
+					page_loader.NewRequest(""),
+				}
+			},
+		}
+
+		var createAnalyzer NewAnalyzer[ExapleModel] = func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			return analyzer, nil
+		}
+
+		crawler_ := NewCrawler(
+			&cache.MockCache{
+				Get_: func(key string) (string, error) {
+					return "", nil
+				},
+			},
+			createAnalyzer,
+			&page_loader.MockPageLoader{},
+			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
+			baseUrl,
+			CrawlerConfig{},
+		).(*crawler[ExapleModel])
+
+		remainingUrlCh := make(chan *page_loader.Request, 5)
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
+		resultCh := make(chan *ExapleModel, 1)
+
+		downloadedUrlCh <- page_loader.NewRequest("asd")
+		assert.True(t, crawler_.AnalyzePage(downloadedUrlCh, remainingUrlCh, resultCh, 1))
+
+		assert.Equal(t, 1, len(remainingUrlCh))
+		assert.Equal(t, baseUrl+"/beers", (<-remainingUrlCh).Url)
+	})
+
+	t.Run("Test LoadPage caches page by request fingerprint", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		var createAnalyzer NewAnalyzer[ExapleModel] = func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			return &MockAnalyzer{}, nil
+		}
+
+		savedKeys := []string{}
+		crawler_ := NewCrawler(
+			&cache.MockCache{
+				Has_: func(key string) bool {
+					return false
+				},
+				Set_: func(key, val string) error {
+					savedKeys = append(savedKeys, key)
+					return nil
+				},
+			},
+			createAnalyzer,
+			&page_loader.MockPageLoader{
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					return &page_loader.Response{StatusCode: http.StatusOK}, nil
+				},
+			},
+			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{},
+		).(*crawler[ExapleModel])
+
+		req := page_loader.NewPostRequest("http://demo.example/search", "application/json", `{"page":2}`)
+		remainingUrlCh := make(chan *page_loader.Request, 1)
+		remainingUrlCh <- req
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
+
+		assert.True(t, crawler_.LoadPage(remainingUrlCh, downloadedUrlCh, 1))
+		assert.Equal(t, req, <-downloadedUrlCh)
+		assert.Equal(t, []string{req.Fingerprint()}, savedKeys)
+		assert.Equal(t, int64(1), crawler_.Stats().Downloaded)
 	})
 
 	t.Run("Test AnalyzePage logs error if creating the analyzer fails", func(t *testing.T) {
@@ -298,8 +798,8 @@ func TestStartCrawling(t *testing.T) {
 			},
 			createAnalyzer,
 			&page_loader.MockPageLoader{
-				LoadPage_: func(url string) (string, error) {
-					return "", nil
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					return &page_loader.Response{StatusCode: http.StatusOK}, nil
 				},
 			},
 			gotils.NewLogger(gotils.LogLevelInfo, outBuf, &bytes.Buffer{}),
@@ -307,9 +807,9 @@ func TestStartCrawling(t *testing.T) {
 			CrawlerConfig{},
 		).(*crawler[ExapleModel])
 
-		remainingUrlCh := make(chan string, 1)
-		downloadedUrlCh := make(chan string, 1)
-		downloadedUrlCh <- "asd"
+		remainingUrlCh := make(chan *page_loader.Request, 1)
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
+		downloadedUrlCh <- page_loader.NewRequest("asd")
 		resultCh := make(chan *ExapleModel, 1)
 
 		assert.True(t, crawler_.AnalyzePage(downloadedUrlCh, remainingUrlCh, resultCh, 1))
@@ -336,8 +836,8 @@ func TestStartCrawling(t *testing.T) {
 			},
 			createAnalyzer,
 			&page_loader.MockPageLoader{
-				LoadPage_: func(url string) (string, error) {
-					return "", nil
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					return &page_loader.Response{StatusCode: http.StatusOK}, nil
 				},
 			},
 			gotils.NewLogger(gotils.LogLevelInfo, outBuf, &bytes.Buffer{}),
@@ -345,17 +845,402 @@ func TestStartCrawling(t *testing.T) {
 			CrawlerConfig{},
 		).(*crawler[ExapleModel])
 
-		remainingUrlCh := make(chan string, 1)
-		downloadedUrlCh := make(chan string, 1)
-		downloadedUrlCh <- "asd"
+		remainingUrlCh := make(chan *page_loader.Request, 1)
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
+		downloadedUrlCh <- page_loader.NewRequest("asd")
 		resultCh := make(chan *ExapleModel, 1)
 
 		assert.True(t, crawler_.AnalyzePage(downloadedUrlCh, remainingUrlCh, resultCh, 1))
 		assert.True(t, strings.Contains(outBuf.String(), err.Error()))
+		assert.Equal(t, int64(1), crawler_.Stats().CacheErrors)
+		assert.Equal(t, int64(0), crawler_.Stats().CacheMisses)
+	})
+}
+
+func TestCrawlerStats(t *testing.T) {
+	t.Run("Counts cache hits and misses", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		var createAnalyzer NewAnalyzer[ExapleModel] = func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			return &MockAnalyzer{}, nil
+		}
+
+		c := cache.NewInstrumentedCache(cache.NewMemoryCache(0, 0))
+		crawler_ := NewCrawler(
+			c,
+			createAnalyzer,
+			&page_loader.MockPageLoader{
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					return &page_loader.Response{Body: "page", StatusCode: http.StatusOK}, nil
+				},
+			},
+			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{},
+		).(*crawler[ExapleModel])
+
+		remainingUrlCh := make(chan *page_loader.Request, 1)
+		downloadedUrlCh := make(chan *page_loader.Request, 2)
+		for i := 0; i < 2; i++ {
+			remainingUrlCh <- page_loader.NewRequest("http://demo.example/1")
+			assert.True(t, crawler_.LoadPage(remainingUrlCh, downloadedUrlCh, 1))
+		}
+		missingUrlCh := make(chan *page_loader.Request, 1)
+		missingUrlCh <- page_loader.NewRequest("http://demo.example/missing")
+		assert.True(t, crawler_.AnalyzePage(missingUrlCh, remainingUrlCh, nil, 1))
+
+		stats := crawler_.Stats()
+		assert.Equal(t, int64(1), stats.Downloaded)
+		assert.Equal(t, int64(1), stats.CacheHits)
+		assert.Equal(t, int64(2), stats.CacheMisses)
+		assert.Equal(t, int64(0), stats.CacheErrors)
+		assert.Equal(t, c.Stats(), stats.Cache)
+		assert.Equal(t, int64(1), stats.Cache.Misses)
+		assert.Equal(t, int64(2), stats.Cache.Lookups)
+		assert.Equal(t, int64(1), stats.Cache.Sets)
+		assert.Equal(t, int64(4), stats.Cache.BytesWritten)
+	})
+}
+
+func TestSkipDuplicates(t *testing.T) {
+	newCrawler := func(c cache.ICache, analyzed *[]string, config CrawlerConfig) *crawler[ExapleModel] {
+		var createAnalyzer NewAnalyzer[ExapleModel] = func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			*analyzed = append(*analyzed, *u)
+			return &MockAnalyzer{
+				GetModel_: func() *ExapleModel { return &ExapleModel{Title: *u} },
+				GetUrls_:  func() []string { return []string{} },
+			}, nil
+		}
+
+		return NewCrawler(
+			c,
+			createAnalyzer,
+			&page_loader.MockPageLoader{},
+			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
+			"http://demo.example",
+			config,
+		).(*crawler[ExapleModel])
+	}
+
+	analyzeAll := func(crawler_ *crawler[ExapleModel], urls ...string) {
+		downloadedUrlCh := make(chan *page_loader.Request, len(urls))
+		resultCh := make(chan *ExapleModel, len(urls))
+		for _, u := range urls {
+			downloadedUrlCh <- page_loader.NewRequest(u)
+			assert.True(t, crawler_.AnalyzePage(downloadedUrlCh, nil, resultCh, 1))
+		}
+	}
+
+	for _, dedup := range []bool{false, true} {
+		t.Run("Skips pages with already analyzed body", func(t *testing.T) {
+			timeout := gotils.NewTimeoutMs(100)
+			go func() { panic(<-timeout.ErrorCh) }()
+			defer timeout.Cancel()
+
+			c := cache.NewMemoryCache(0, 0)
+			if dedup {
+				c = cache.NewDedupCache(cache.NewMemoryCache(0, 0), cache.NewMemoryCache(0, 0))
+			}
+			gotils.NilOrPanic(c.Set("http://demo.example/1", "page"))
+			gotils.NilOrPanic(c.Set("http://demo.example/1?print=1", "page"))
+			gotils.NilOrPanic(c.Set("http://demo.example/2", "other page"))
+			analyzed := []string{}
+			crawler_ := newCrawler(c, &analyzed, CrawlerConfig{SkipDuplicates: true})
+
+			analyzeAll(crawler_, "http://demo.example/1", "http://demo.example/1?print=1", "http://demo.example/2")
+
+			assert.Equal(t, []string{"http://demo.example/1", "http://demo.example/2"}, analyzed)
+			assert.Equal(t, CrawlerStats{Analyzed: 2, Models: 2, Duplicates: 1}, crawler_.Stats())
+		})
+	}
+
+	t.Run("Analyzes duplicates if not enabled", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		c := cache.NewMemoryCache(0, 0)
+		gotils.NilOrPanic(c.Set("http://demo.example/1", "page"))
+		gotils.NilOrPanic(c.Set("http://demo.example/1?print=1", "page"))
+		analyzed := []string{}
+		crawler_ := newCrawler(c, &analyzed, CrawlerConfig{})
+
+		analyzeAll(crawler_, "http://demo.example/1", "http://demo.example/1?print=1")
+
+		assert.Equal(t, 2, len(analyzed))
+		assert.Equal(t, int64(0), crawler_.Stats().Duplicates)
 	})
 }
 
-func newMockCrawler() ICrawler[ExapleModel] {
+func TestNearDuplicates(t *testing.T) {
+	pages := map[string]string{
+		"http://demo.example/1":          newArticle("beer", ""),
+		"http://demo.example/1?session=": newArticle("beer", "<footer>Session 12345</footer>"),
+	}
+
+	newCrawler := func(analyzed *[]string, action NearDuplicateAction) *crawler[ExapleModel] {
+		var createAnalyzer NewAnalyzer[ExapleModel] = func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			*analyzed = append(*analyzed, *u)
+			return &MockAnalyzer{
+				GetModel_: func() *ExapleModel { return &ExapleModel{Title: *u} },
+				GetUrls_:  func() []string { return []string{*u + "/next"} },
+			}, nil
+		}
+
+		return NewCrawler(
+			&cache.MockCache{
+				Get_: func(key string) (string, error) { return pages[key], nil },
+			},
+			createAnalyzer,
+			&page_loader.MockPageLoader{},
+			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{NearDuplicates: &NearDuplicateConfig{Action: action}},
+		).(*crawler[ExapleModel])
+	}
+
+	analyzeAll := func(crawler_ *crawler[ExapleModel]) ([]*ExapleModel, []string) {
+		downloadedUrlCh := make(chan *page_loader.Request, 1)
+		remainingUrlCh := make(chan *page_loader.Request, 2)
+		resultCh := make(chan *ExapleModel, 2)
+		for _, u := range []string{"http://demo.example/1", "http://demo.example/1?session="} {
+			downloadedUrlCh <- page_loader.NewRequest(u)
+			assert.True(t, crawler_.AnalyzePage(downloadedUrlCh, remainingUrlCh, resultCh, 1))
+		}
+		close(resultCh)
+		close(remainingUrlCh)
+
+		models := []*ExapleModel{}
+		for model := range resultCh {
+			models = append(models, model)
+		}
+
+		urls := []string{}
+		for req := range remainingUrlCh {
+			urls = append(urls, req.Url)
+		}
+
+		return models, urls
+	}
+
+	t.Run("Skips analysis of near-duplicate page", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+		analyzed := []string{}
+		crawler_ := newCrawler(&analyzed, 0)
+
+		models, urls := analyzeAll(crawler_)
+
+		assert.Equal(t, []string{"http://demo.example/1"}, analyzed)
+		assert.Equal(t, 1, len(models))
+		assert.Equal(t, []string{"http://demo.example/1/next"}, urls)
+		assert.Equal(t, int64(1), crawler_.Stats().NearDuplicates)
+	})
+
+	t.Run("Suppresses models of near-duplicate page", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+		analyzed := []string{}
+		crawler_ := newCrawler(&analyzed, SkipNearDuplicateModels)
+
+		models, urls := analyzeAll(crawler_)
+
+		assert.Equal(t, 2, len(analyzed))
+		assert.Equal(t, []*ExapleModel{{Title: "http://demo.example/1"}}, models)
+		assert.Equal(t, []string{"http://demo.example/1/next", "http://demo.example/1?session=/next"}, urls)
+	})
+
+	t.Run("Suppresses URLs of near-duplicate page", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+		analyzed := []string{}
+		crawler_ := newCrawler(&analyzed, SkipNearDuplicateUrls)
+
+		models, urls := analyzeAll(crawler_)
+
+		assert.Equal(t, 2, len(models))
+		assert.Equal(t, []string{"http://demo.example/1/next"}, urls)
+	})
+}
+
+func TestReanalyze(t *testing.T) {
+	newLinkAnalyzer := func(links map[string][]string) NewAnalyzer[ExapleModel] {
+		return func(html, u *string) (IAnalyzer[ExapleModel], error) {
+			model := &ExapleModel{Title: *u, Content: *html}
+			return &MockAnalyzer{
+				GetModel_: func() *ExapleModel { return model },
+				GetUrls_:  func() []string { return links[*u] },
+			}, nil
+		}
+	}
+
+	t.Run("Replays link graph from cache", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(1100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		c := cache.NewMemoryCache(0, 0)
+		gotils.NilOrPanic(c.Set("http://demo.example", "index"))
+		gotils.NilOrPanic(c.Set("http://demo.example/1", "page1"))
+		links := map[string][]string{"http://demo.example": {"/1"}}
+
+		crawler := NewCrawler(
+			c,
+			newLinkAnalyzer(links),
+			&page_loader.MockPageLoader{
+				LoadPage_: func(req *page_loader.Request) (*page_loader.Response, error) {
+					panic("page loader must not be used")
+				},
+			},
+			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{},
+		)
+
+		result := []string{}
+		for item := range crawler.Reanalyze("http://demo.example") {
+			result = append(result, item.Content)
+			if len(result) == 2 {
+				break
+			}
+		}
+
+		crawler.Stop()
+		crawler.WaitStopped()
+		assert.Equal(t, []string{"index", "page1"}, result)
+	})
+
+	t.Run("Stops on cache miss", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(1100)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		c := cache.NewMemoryCache(0, 0)
+		gotils.NilOrPanic(c.Set("http://demo.example", "index"))
+		links := map[string][]string{"http://demo.example": {"/missing"}}
+		outBuf := &bytes.Buffer{}
+
+		crawler := NewCrawler(
+			c,
+			newLinkAnalyzer(links),
+			&page_loader.MockPageLoader{},
+			gotils.NewLogger(gotils.LogLevelInfo, outBuf, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{},
+		)
+
+		result := []string{}
+		for item := range crawler.Reanalyze("http://demo.example") {
+			result = append(result, item.Content)
+		}
+		crawler.WaitStopped()
+
+		assert.Equal(t, []string{"index"}, result)
+		assert.ErrorIs(t, crawler.Err(), ErrorNotCached)
+		assert.Contains(t, crawler.Err().Error(), "http://demo.example/missing")
+		assert.True(t, strings.Contains(outBuf.String(), "http://demo.example/missing"))
+		assert.True(t, strings.Contains(outBuf.String(), ErrorNotCached.Error()))
+	})
+
+	t.Run("Analyzes all cached pages", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(1000)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		c := cache.NewMemoryCache(0, 0).(cache.IMetadataCache)
+		gotils.NilOrPanic(c.Set("http://demo.example", "index"))
+		gotils.NilOrPanic(c.Set("http://demo.example/1", "page1"))
+		gotils.NilOrPanic(c.Set("http://demo.example/orphan", "orphan"))
+		post := page_loader.NewPostRequest("http://demo.example/search", "application/json", "{}")
+		gotils.NilOrPanic(c.Set(post.Fingerprint(), "search"))
+		gotils.NilOrPanic(c.SetMetadata(post.Fingerprint(), &cache.Metadata{Method: post.Method, Url: post.Url, RequestBody: post.Body}))
+		gotils.NilOrPanic(c.Set("POST http://demo.example/unknown 00", "unknown"))
+		links := map[string][]string{"http://demo.example": {"/1", "/uncached"}}
+		outBuf := &bytes.Buffer{}
+
+		crawler := NewCrawler(
+			c,
+			newLinkAnalyzer(links),
+			&page_loader.MockPageLoader{},
+			gotils.NewLogger(gotils.LogLevelInfo, outBuf, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{PageAnalyzers: 2},
+		)
+
+		result := map[string]string{}
+		for item := range crawler.ReanalyzeAll() {
+			result[item.Title] = item.Content
+		}
+
+		crawler.WaitStopped()
+		assert.Nil(t, crawler.Err())
+		assert.Equal(t, map[string]string{
+			"http://demo.example":        "index",
+			"http://demo.example/1":      "page1",
+			"http://demo.example/orphan": "orphan",
+			"http://demo.example/search": "search",
+		}, result)
+		assert.Contains(t, outBuf.String(), "POST http://demo.example/unknown 00")
+	})
+
+	t.Run("Stops analyzing all cached pages", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(1000)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		c := cache.NewMemoryCache(0, 0)
+		for i := 0; i < 100; i++ {
+			gotils.NilOrPanic(c.Set(fmt.Sprintf("http://demo.example/%d", i), "page"))
+		}
+
+		crawler := NewCrawler(
+			c,
+			newLinkAnalyzer(nil),
+			&page_loader.MockPageLoader{},
+			gotils.NewLogger(gotils.LogLevelInfo, &bytes.Buffer{}, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{ResultChSize: 1},
+		)
+
+		resultCh := crawler.ReanalyzeAll()
+		<-resultCh
+		crawler.Stop()
+		crawler.WaitStopped()
+
+		for range resultCh {
+		}
+	})
+
+	t.Run("Closes result channel if cache can't list keys", func(t *testing.T) {
+		timeout := gotils.NewTimeoutMs(1000)
+		go func() { panic(<-timeout.ErrorCh) }()
+		defer timeout.Cancel()
+
+		outBuf := &bytes.Buffer{}
+		crawler := NewCrawler(
+			&cache.MockCache{},
+			newLinkAnalyzer(nil),
+			&page_loader.MockPageLoader{},
+			gotils.NewLogger(gotils.LogLevelInfo, outBuf, &bytes.Buffer{}),
+			"http://demo.example",
+			CrawlerConfig{},
+		)
+
+		_, ok := <-crawler.ReanalyzeAll()
+		crawler.WaitStopped()
+
+		assert.False(t, ok)
+		assert.Equal(t, cache.ErrorNotIterable, crawler.Err())
+		assert.True(t, strings.Contains(outBuf.String(), "listing keys"))
+	})
+}
+
+func newMockCrawler() IStatsCrawler[ExapleModel] {
 	return &MockCrawler[ExapleModel]{}
 }
 
@@ -380,6 +1265,52 @@ func TestMockCrawler(t *testing.T) {
 		assert.Equal(t, startingUrl, result.Title)
 	})
 
+	t.Run("Test Reanalyze", func(t *testing.T) {
+		crawler := newMockCrawler().(*MockCrawler[ExapleModel])
+		ch := make(chan *ExapleModel)
+		startingUrl := ""
+		crawler.Reanalyze_ = func(u string) <-chan *ExapleModel {
+			startingUrl = u
+			return ch
+		}
+
+		resultCh := crawler.Reanalyze("http://example.com")
+
+		assert.Equal(t, "http://example.com", startingUrl)
+		assert.Equal(t, (<-chan *ExapleModel)(ch), resultCh)
+	})
+
+	t.Run("Test ReanalyzeAll", func(t *testing.T) {
+		crawler := newMockCrawler().(*MockCrawler[ExapleModel])
+		ch := make(chan *ExapleModel)
+		crawler.ReanalyzeAll_ = func() <-chan *ExapleModel {
+			return ch
+		}
+
+		resultCh := crawler.ReanalyzeAll()
+
+		assert.Equal(t, (<-chan *ExapleModel)(ch), resultCh)
+	})
+
+	t.Run("Test Err", func(t *testing.T) {
+		crawler := newMockCrawler().(*MockCrawler[ExapleModel])
+		crawler.Err_ = func() error {
+			return ErrorNotCached
+		}
+
+		assert.Equal(t, ErrorNotCached, crawler.Err())
+	})
+
+	t.Run("Test Stats", func(t *testing.T) {
+		crawler := newMockCrawler().(*MockCrawler[ExapleModel])
+		stats := CrawlerStats{Downloaded: 1}
+		crawler.Stats_ = func() CrawlerStats {
+			return stats
+		}
+
+		assert.Equal(t, stats, crawler.Stats())
+	})
+
 	t.Run("Test Stop", func(t *testing.T) {
 		crawler := newMockCrawler().(*MockCrawler[ExapleModel])
 
@@ -0,0 +1,77 @@
+package grawler
+
+import (
+	"sync"
+
+	"github.com/DAtek/grawler/page_loader"
+)
+
+type hostQueue struct {
+	requests []*page_loader.Request
+	released chan struct{}
+}
+
+type deferredQueue struct {
+	mutex *sync.Mutex
+	hosts map[string]*hostQueue
+}
+
+func newDeferredQueue() *deferredQueue {
+	return &deferredQueue{
+		mutex: &sync.Mutex{},
+		hosts: map[string]*hostQueue{},
+	}
+}
+
+func (q *deferredQueue) add(host string, req *page_loader.Request) (<-chan struct{}, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	queue, ok := q.hosts[host]
+	if !ok {
+		queue = &hostQueue{released: make(chan struct{}, 1)}
+		q.hosts[host] = queue
+	}
+
+	queue.requests = append(queue.requests, req)
+	return queue.released, !ok
+}
+
+func (q *deferredQueue) release(host string) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	queue, ok := q.hosts[host]
+	if !ok {
+		return
+	}
+
+	select {
+	case queue.released <- struct{}{}:
+	default:
+	}
+}
+
+func (q *deferredQueue) take(host string, all bool) ([]*page_loader.Request, bool) {
+	q.mutex.Lock()
+	defer q.mutex.Unlock()
+
+	queue, ok := q.hosts[host]
+	if !ok {
+		return nil, true
+	}
+
+	n := 1
+	if all {
+		n = len(queue.requests)
+	}
+
+	requests := queue.requests[:n]
+	queue.requests = queue.requests[n:]
+	if len(queue.requests) > 0 {
+		return requests, false
+	}
+
+	delete(q.hosts, host)
+	return requests, true
+}
@@ -0,0 +1,64 @@
+package grawler
+
+import (
+	"testing"
+
+	"github.com/DAtek/grawler/page_loader"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeferredQueue(t *testing.T) {
+	t.Run("Reports the first request of a host", func(t *testing.T) {
+		q := newDeferredQueue()
+
+		_, isNew1 := q.add("demo.example", page_loader.NewRequest("http://demo.example/1"))
+		_, isNew2 := q.add("demo.example", page_loader.NewRequest("http://demo.example/2"))
+		_, isNew3 := q.add("other.example", page_loader.NewRequest("http://other.example/1"))
+
+		assert.True(t, isNew1)
+		assert.False(t, isNew2)
+		assert.True(t, isNew3)
+	})
+
+	t.Run("Takes one request as probe", func(t *testing.T) {
+		q := newDeferredQueue()
+		req1 := page_loader.NewRequest("http://demo.example/1")
+		req2 := page_loader.NewRequest("http://demo.example/2")
+		q.add("demo.example", req1)
+		q.add("demo.example", req2)
+
+		requests1, done1 := q.take("demo.example", false)
+		requests2, done2 := q.take("demo.example", false)
+
+		assert.Equal(t, []*page_loader.Request{req1}, requests1)
+		assert.False(t, done1)
+		assert.Equal(t, []*page_loader.Request{req2}, requests2)
+		assert.True(t, done2)
+	})
+
+	t.Run("Takes all requests", func(t *testing.T) {
+		q := newDeferredQueue()
+		req1 := page_loader.NewRequest("http://demo.example/1")
+		req2 := page_loader.NewRequest("http://demo.example/2")
+		q.add("demo.example", req1)
+		q.add("demo.example", req2)
+
+		requests, done := q.take("demo.example", true)
+		_, isNew := q.add("demo.example", req1)
+
+		assert.Equal(t, []*page_loader.Request{req1, req2}, requests)
+		assert.True(t, done)
+		assert.True(t, isNew)
+	})
+
+	t.Run("Signals release once", func(t *testing.T) {
+		q := newDeferredQueue()
+		released, _ := q.add("demo.example", page_loader.NewRequest("http://demo.example/1"))
+
+		q.release("demo.example")
+		q.release("demo.example")
+		q.release("other.example")
+
+		assert.Equal(t, 1, len(released))
+	})
+}
@@ -1,16 +1,19 @@
 module github.com/DAtek/grawler
 
-go 1.20
+go 1.22
 
 require (
 	github.com/DAtek/gotils v0.1.3
 	github.com/andybalholm/brotli v1.0.5
 	github.com/deckarep/golang-set/v2 v2.3.0
+	github.com/klauspost/compress v1.18.0
 	github.com/stretchr/testify v1.8.3
+	go.etcd.io/bbolt v1.3.11
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/sys v0.22.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
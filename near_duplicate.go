@@ -0,0 +1,176 @@
+package grawler
+
+import (
+	"hash/fnv"
+	"math/bits"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+type NearDuplicateAction int
+
+const (
+	SkipNearDuplicateModels NearDuplicateAction = 1 << iota
+	SkipNearDuplicateUrls
+)
+
+type NearDuplicateConfig struct {
+	MaxDistance int
+	MinFeatures int
+	Action      NearDuplicateAction
+}
+
+func (c *NearDuplicateConfig) validate() {
+	if c.MaxDistance <= 0 {
+		c.MaxDistance = 3
+	}
+
+	if c.MinFeatures <= 0 {
+		c.MinFeatures = 10
+	}
+
+	if c.Action == 0 {
+		c.Action = SkipNearDuplicateModels | SkipNearDuplicateUrls
+	}
+}
+
+const shingleSize = 3
+
+var (
+	ignoredElementsRegex = regexp.MustCompile(`(?is)<(script|style)[^>]*>.*?</(script|style)>`)
+	tagRegex             = regexp.MustCompile(`<[^>]*>`)
+)
+
+func SimHash(page string) uint64 {
+	hash, _ := simHash(page)
+	return hash
+}
+
+func simHash(page string) (uint64, int) {
+	words := normalizeText(page)
+	size := shingleSize
+	if len(words) < size {
+		size = len(words)
+	}
+
+	weights := [64]int{}
+	features := 0
+	for i := 0; i+size <= len(words) && size > 0; i++ {
+		features++
+		hash := fnv.New64a()
+		hash.Write([]byte(strings.Join(words[i:i+size], " ")))
+		feature := hash.Sum64()
+
+		for bit := 0; bit < 64; bit++ {
+			if feature&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var result uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			result |= 1 << bit
+		}
+	}
+
+	return result, features
+}
+
+func HammingDistance(x, y uint64) int {
+	return bits.OnesCount64(x ^ y)
+}
+
+func normalizeText(page string) []string {
+	text := ignoredElementsRegex.ReplaceAllString(page, " ")
+	text = tagRegex.ReplaceAllString(text, " ")
+
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+type nearDuplicateDetector struct {
+	maxDistance int
+	minFeatures int
+	blocks      []hashBlock
+	count       int
+	mutex       *sync.Mutex
+}
+
+type hashBlock struct {
+	shift  int
+	mask   uint64
+	hashes map[uint64][]uint64
+}
+
+func newNearDuplicateDetector(maxDistance, minFeatures int) *nearDuplicateDetector {
+	count := min(maxDistance+1, 64)
+	blocks := make([]hashBlock, count)
+	for i := range blocks {
+		start, end := i*64/count, (i+1)*64/count
+		blocks[i] = hashBlock{
+			shift:  start,
+			mask:   1<<(end-start) - 1,
+			hashes: map[uint64][]uint64{},
+		}
+	}
+
+	return &nearDuplicateDetector{
+		maxDistance: maxDistance,
+		minFeatures: minFeatures,
+		blocks:      blocks,
+		mutex:       &sync.Mutex{},
+	}
+}
+
+func (d *nearDuplicateDetector) check(page string) bool {
+	hash, features := simHash(page)
+	if features < d.minFeatures {
+		return false
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.contains(hash) {
+		return true
+	}
+
+	d.add(hash)
+	return false
+}
+
+func (d *nearDuplicateDetector) contains(hash uint64) bool {
+	if d.maxDistance >= 64 {
+		return d.count > 0
+	}
+
+	for _, block := range d.blocks {
+		for _, candidate := range block.hashes[block.key(hash)] {
+			if HammingDistance(hash, candidate) <= d.maxDistance {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (d *nearDuplicateDetector) add(hash uint64) {
+	for _, block := range d.blocks {
+		key := block.key(hash)
+		block.hashes[key] = append(block.hashes[key], hash)
+	}
+
+	d.count++
+}
+
+func (b hashBlock) key(hash uint64) uint64 {
+	return hash >> b.shift & b.mask
+}
@@ -0,0 +1,112 @@
+package grawler
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newArticle(title, extra string) string {
+	paragraphs := []string{}
+	for i := 0; i < 30; i++ {
+		paragraphs = append(paragraphs, fmt.Sprintf("<p>Paragraph %d of the article about %s with some boilerplate text</p>", i, title))
+	}
+
+	return "<html><head><script>var tracking = 1;</script></head><body>" +
+		strings.Join(paragraphs, "\n") + extra + "</body></html>"
+}
+
+func TestSimHash(t *testing.T) {
+	t.Run("Near-duplicate pages have small distance", func(t *testing.T) {
+		page := newArticle("beer", "")
+		nearDuplicate := newArticle("beer", "<footer>Session 12345</footer>")
+
+		distance := HammingDistance(SimHash(page), SimHash(nearDuplicate))
+
+		assert.LessOrEqual(t, distance, 3)
+	})
+
+	t.Run("Different pages have large distance", func(t *testing.T) {
+		page := newArticle("beer", "")
+		other := "<html><body><h1>Wine list</h1><p>Red, white and rosé wines from all over Europe.</p></body></html>"
+
+		distance := HammingDistance(SimHash(page), SimHash(other))
+
+		assert.Greater(t, distance, 10)
+	})
+
+	t.Run("Ignores markup, scripts and case", func(t *testing.T) {
+		page := `<html><style>p { color: red; }</style><body><P class="x">Lager Beer</P></body></html>`
+
+		assert.Equal(t, SimHash("lager beer"), SimHash(page))
+		assert.Equal(t, []string{"lager", "beer"}, normalizeText(page))
+	})
+
+	t.Run("Hashes empty page", func(t *testing.T) {
+		assert.Equal(t, uint64(0), SimHash(""))
+	})
+}
+
+func TestNearDuplicateDetector(t *testing.T) {
+	t.Run("Detects near-duplicate of seen page", func(t *testing.T) {
+		detector := newNearDuplicateDetector(3, 10)
+
+		assert.False(t, detector.check(newArticle("beer", "")))
+		assert.True(t, detector.check(newArticle("beer", "<footer>Session 12345</footer>")))
+		assert.False(t, detector.check(newArticle("wine", "")))
+	})
+
+	t.Run("Detects only identical fingerprints with zero distance", func(t *testing.T) {
+		detector := newNearDuplicateDetector(0, 1)
+
+		assert.False(t, detector.check("lager beer"))
+		assert.True(t, detector.check("<b>Lager</b> beer"))
+	})
+
+	t.Run("Ignores pages with too few features", func(t *testing.T) {
+		detector := newNearDuplicateDetector(3, 10)
+
+		assert.False(t, detector.check(""))
+		assert.False(t, detector.check("<html><script>var tracking = 1;</script></html>"))
+		assert.False(t, detector.check(""))
+		assert.False(t, detector.check("lager beer"))
+		assert.False(t, detector.check("lager beer"))
+	})
+
+	t.Run("Finds fingerprints within distance among many", func(t *testing.T) {
+		detector := newNearDuplicateDetector(3, 1)
+		random := rand.New(rand.NewSource(42))
+		hashes := []uint64{}
+		for i := 0; i < 1000; i++ {
+			hash := random.Uint64()
+			hashes = append(hashes, hash)
+			detector.add(hash)
+		}
+
+		for _, hash := range hashes {
+			flipped := hash ^ 1<<3 ^ 1<<21 ^ 1<<60
+			assert.True(t, detector.contains(flipped))
+			assert.False(t, detector.contains(flipped^1<<40))
+		}
+	})
+
+	t.Run("Detects any seen page if distance covers all bits", func(t *testing.T) {
+		detector := newNearDuplicateDetector(64, 1)
+
+		assert.False(t, detector.check("lager beer"))
+		assert.True(t, detector.check("red wine"))
+	})
+
+	t.Run("Uses default distance, features and action", func(t *testing.T) {
+		config := &NearDuplicateConfig{MaxDistance: -1}
+
+		config.validate()
+
+		assert.Equal(t, 3, config.MaxDistance)
+		assert.Equal(t, 10, config.MinFeatures)
+		assert.Equal(t, SkipNearDuplicateModels|SkipNearDuplicateUrls, config.Action)
+	})
+}
@@ -1,10 +1,16 @@
 package grawler
 
+import "github.com/DAtek/grawler/page_loader"
+
 type IAnalyzer[T any] interface {
 	GetUrls() []string
 	GetModel() *T
 }
 
+type IRequestAnalyzer interface {
+	GetRequests() []*page_loader.Request
+}
+
 type NewAnalyzer[T any] func(html, source *string) (IAnalyzer[T], error)
 
 type MockAnalyzer struct {
@@ -24,3 +30,12 @@ func (m *MockAnalyzer) GetUrls() []string {
 func (m *MockAnalyzer) GetModel() *ExapleModel {
 	return m.GetModel_()
 }
+
+type MockRequestAnalyzer struct {
+	MockAnalyzer
+	GetRequests_ func() []*page_loader.Request
+}
+
+func (m *MockRequestAnalyzer) GetRequests() []*page_loader.Request {
+	return m.GetRequests_()
+}
@@ -0,0 +1,186 @@
+package page_loader
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/DAtek/gotils"
+)
+
+const ErrorCircuitOpen = gotils.Error("CIRCUIT_OPEN")
+
+type BreakerState int
+
+const (
+	BreakerClosed = BreakerState(iota)
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+
+	return "unknown"
+}
+
+type CircuitBreakerConfig struct {
+	FailureThreshold int
+	OpenDuration     time.Duration
+	HalfOpenProbes   int
+	IsFailure        func(err error) bool
+	OnStateChange    func(host string, from, to BreakerState)
+}
+
+func (c *CircuitBreakerConfig) validate() {
+	c.FailureThreshold = max(c.FailureThreshold, 1)
+	c.HalfOpenProbes = max(c.HalfOpenProbes, 1)
+
+	if c.OpenDuration <= 0 {
+		c.OpenDuration = 30 * time.Second
+	}
+
+	if c.IsFailure == nil {
+		c.IsFailure = isServerFailure
+	}
+}
+
+type hostBreaker struct {
+	state     BreakerState
+	failures  int
+	probes    int
+	successes int
+	openedAt  time.Time
+}
+
+type circuitBreakerPageLoader struct {
+	loader   IPageLoader
+	config   *CircuitBreakerConfig
+	mutex    *sync.Mutex
+	breakers map[string]*hostBreaker
+	now      func() time.Time
+}
+
+func NewCircuitBreakerPageLoader(loader IPageLoader, config CircuitBreakerConfig) IPageLoader {
+	config.validate()
+	return &circuitBreakerPageLoader{
+		loader:   loader,
+		config:   &config,
+		mutex:    &sync.Mutex{},
+		breakers: map[string]*hostBreaker{},
+		now:      time.Now,
+	}
+}
+
+func (l *circuitBreakerPageLoader) LoadPage(req *Request) (*Response, error) {
+	host := GetHost(req.Url)
+
+	if err := l.before(host); err != nil {
+		return nil, err
+	}
+
+	resp, err := l.loader.LoadPage(req)
+	l.after(host, err != nil && l.config.IsFailure(err))
+	return resp, err
+}
+
+func (l *circuitBreakerPageLoader) before(host string) error {
+	l.mutex.Lock()
+	breaker, ok := l.breakers[host]
+	if !ok {
+		breaker = &hostBreaker{}
+		l.breakers[host] = breaker
+	}
+
+	from := breaker.state
+	if breaker.state == BreakerOpen && !l.now().Before(breaker.openedAt.Add(l.config.OpenDuration)) {
+		breaker.state = BreakerHalfOpen
+		breaker.probes = 0
+		breaker.successes = 0
+	}
+
+	var err error
+	switch {
+	case breaker.state == BreakerOpen:
+		err = ErrorCircuitOpen
+	case breaker.state == BreakerHalfOpen && breaker.probes >= l.config.HalfOpenProbes:
+		err = ErrorCircuitOpen
+	case breaker.state == BreakerHalfOpen:
+		breaker.probes++
+	}
+
+	to := breaker.state
+	l.mutex.Unlock()
+	l.notify(host, from, to)
+	return err
+}
+
+func (l *circuitBreakerPageLoader) after(host string, failed bool) {
+	l.mutex.Lock()
+	breaker := l.breakers[host]
+	from := breaker.state
+
+	switch breaker.state {
+	case BreakerClosed:
+		if !failed {
+			breaker.failures = 0
+			break
+		}
+
+		breaker.failures++
+		if breaker.failures >= l.config.FailureThreshold {
+			breaker.state = BreakerOpen
+			breaker.openedAt = l.now()
+		}
+	case BreakerHalfOpen:
+		breaker.probes--
+		if failed {
+			breaker.state = BreakerOpen
+			breaker.openedAt = l.now()
+			break
+		}
+
+		breaker.successes++
+		if breaker.successes >= l.config.HalfOpenProbes {
+			breaker.state = BreakerClosed
+			breaker.failures = 0
+		}
+	}
+
+	to := breaker.state
+	l.mutex.Unlock()
+	l.notify(host, from, to)
+}
+
+func (l *circuitBreakerPageLoader) notify(host string, from, to BreakerState) {
+	if from != to && l.config.OnStateChange != nil {
+		l.config.OnStateChange(host, from, to)
+	}
+}
+
+func isServerFailure(err error) bool {
+	statusErr := &StatusError{}
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+
+	return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+}
+
+func GetHost(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil {
+		return ""
+	}
+
+	return u.Host
+}
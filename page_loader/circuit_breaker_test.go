@@ -0,0 +1,151 @@
+package page_loader
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stateChange struct {
+	host string
+	from BreakerState
+	to   BreakerState
+}
+
+func TestCircuitBreakerPageLoader(t *testing.T) {
+	failingUrl := "http://failing.example/1"
+	otherUrl := "http://other.example/1"
+
+	newBreaker := func(config CircuitBreakerConfig) (*circuitBreakerPageLoader, *bool, *[]stateChange, *time.Time) {
+		fail := true
+		changes := &[]stateChange{}
+		now := time.Now()
+		config.OnStateChange = func(host string, from, to BreakerState) {
+			*changes = append(*changes, stateChange{host, from, to})
+		}
+		loader := NewCircuitBreakerPageLoader(&MockPageLoader{
+			LoadPage_: func(req *Request) (*Response, error) {
+				if fail && req.Url == failingUrl {
+					return nil, errors.New("TIMEOUT")
+				}
+				return &Response{StatusCode: http.StatusOK}, nil
+			},
+		}, config).(*circuitBreakerPageLoader)
+		loader.now = func() time.Time { return now }
+		return loader, &fail, changes, &now
+	}
+
+	t.Run("Opens after reaching the failure threshold", func(t *testing.T) {
+		loader, _, changes, _ := newBreaker(CircuitBreakerConfig{FailureThreshold: 2})
+
+		_, err1 := loader.LoadPage(NewRequest(failingUrl))
+		_, err2 := loader.LoadPage(NewRequest(failingUrl))
+		_, err3 := loader.LoadPage(NewRequest(failingUrl))
+
+		assert.EqualError(t, err1, "TIMEOUT")
+		assert.EqualError(t, err2, "TIMEOUT")
+		assert.Equal(t, ErrorCircuitOpen, err3)
+		assert.Equal(t, []stateChange{{"failing.example", BreakerClosed, BreakerOpen}}, *changes)
+	})
+
+	t.Run("Does not affect other hosts", func(t *testing.T) {
+		loader, _, _, _ := newBreaker(CircuitBreakerConfig{FailureThreshold: 1})
+
+		loader.LoadPage(NewRequest(failingUrl))
+		res, err := loader.LoadPage(NewRequest(otherUrl))
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Successful request resets failures", func(t *testing.T) {
+		loader, fail, _, _ := newBreaker(CircuitBreakerConfig{FailureThreshold: 2})
+
+		loader.LoadPage(NewRequest(failingUrl))
+		*fail = false
+		loader.LoadPage(NewRequest(failingUrl))
+		*fail = true
+		_, err := loader.LoadPage(NewRequest(failingUrl))
+
+		assert.EqualError(t, err, "TIMEOUT")
+	})
+
+	t.Run("Closes after successful probes", func(t *testing.T) {
+		loader, fail, changes, now := newBreaker(CircuitBreakerConfig{
+			FailureThreshold: 1,
+			OpenDuration:     time.Minute,
+			HalfOpenProbes:   2,
+		})
+
+		loader.LoadPage(NewRequest(failingUrl))
+		*now = now.Add(time.Minute)
+		*fail = false
+		_, err1 := loader.LoadPage(NewRequest(failingUrl))
+		_, err2 := loader.LoadPage(NewRequest(failingUrl))
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Equal(t, []stateChange{
+			{"failing.example", BreakerClosed, BreakerOpen},
+			{"failing.example", BreakerOpen, BreakerHalfOpen},
+			{"failing.example", BreakerHalfOpen, BreakerClosed},
+		}, *changes)
+	})
+
+	t.Run("Opens again if probe fails", func(t *testing.T) {
+		loader, _, changes, now := newBreaker(CircuitBreakerConfig{
+			FailureThreshold: 1,
+			OpenDuration:     time.Minute,
+		})
+
+		loader.LoadPage(NewRequest(failingUrl))
+		*now = now.Add(time.Minute)
+		_, err1 := loader.LoadPage(NewRequest(failingUrl))
+		_, err2 := loader.LoadPage(NewRequest(failingUrl))
+
+		assert.EqualError(t, err1, "TIMEOUT")
+		assert.Equal(t, ErrorCircuitOpen, err2)
+		assert.Equal(t, []stateChange{
+			{"failing.example", BreakerClosed, BreakerOpen},
+			{"failing.example", BreakerOpen, BreakerHalfOpen},
+			{"failing.example", BreakerHalfOpen, BreakerOpen},
+		}, *changes)
+	})
+
+	t.Run("Limits concurrent probes", func(t *testing.T) {
+		loader, _, _, now := newBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenDuration: time.Minute})
+
+		loader.LoadPage(NewRequest(failingUrl))
+		*now = now.Add(time.Minute)
+		assert.Nil(t, loader.before("failing.example"))
+
+		assert.Equal(t, ErrorCircuitOpen, loader.before("failing.example"))
+	})
+
+	t.Run("Client errors are not failures", func(t *testing.T) {
+		assert.False(t, isServerFailure(&StatusError{StatusCode: http.StatusNotFound}))
+		assert.True(t, isServerFailure(&StatusError{StatusCode: http.StatusTooManyRequests}))
+		assert.True(t, isServerFailure(&StatusError{StatusCode: http.StatusBadGateway}))
+		assert.True(t, isServerFailure(errors.New("TIMEOUT")))
+	})
+
+	t.Run("Breaker states have names", func(t *testing.T) {
+		assert.Equal(t, "closed", BreakerClosed.String())
+		assert.Equal(t, "open", BreakerOpen.String())
+		assert.Equal(t, "half-open", BreakerHalfOpen.String())
+		assert.Equal(t, "unknown", BreakerState(42).String())
+	})
+}
+
+func TestGetHost(t *testing.T) {
+	t.Run("Returns host of URL", func(t *testing.T) {
+		assert.Equal(t, "demo.example:8080", GetHost("http://demo.example:8080/beers?page=2"))
+	})
+
+	t.Run("Returns empty string for invalid URL", func(t *testing.T) {
+		assert.Equal(t, "", GetHost("http://demo.example/%zz"))
+	})
+}
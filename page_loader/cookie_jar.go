@@ -0,0 +1,125 @@
+package page_loader
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/DAtek/gotils"
+)
+
+type ICookieJar interface {
+	http.CookieJar
+	Save(path string) error
+}
+
+type storedCookie struct {
+	Url    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+type cookieJar struct {
+	jar     *cookiejar.Jar
+	mutex   *sync.Mutex
+	cookies map[string]*storedCookie
+}
+
+func NewCookieJar() ICookieJar {
+	return &cookieJar{
+		jar:     gotils.ResultOrPanic(cookiejar.New(nil)),
+		mutex:   &sync.Mutex{},
+		cookies: map[string]*storedCookie{},
+	}
+}
+
+func LoadCookieJar(path string) (ICookieJar, error) {
+	jar := NewCookieJar().(*cookieJar)
+	data, err := os.ReadFile(path)
+
+	if errors.Is(err, os.ErrNotExist) {
+		return jar, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	stored := []*storedCookie{}
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+
+	for _, item := range stored {
+		u, err := url.Parse(item.Url)
+		if err != nil {
+			return nil, err
+		}
+		jar.SetCookies(u, []*http.Cookie{item.Cookie})
+	}
+
+	return jar, nil
+}
+
+func (j *cookieJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	j.jar.SetCookies(u, cookies)
+	now := time.Now()
+
+	for _, cookie := range cookies {
+		cookie := *cookie
+		key := cookieKey(u, &cookie)
+
+		if cookie.MaxAge > 0 {
+			cookie.Expires = now.Add(time.Duration(cookie.MaxAge) * time.Second)
+			cookie.MaxAge = 0
+		}
+
+		if cookie.MaxAge < 0 || (!cookie.Expires.IsZero() && !cookie.Expires.After(now)) {
+			delete(j.cookies, key)
+			continue
+		}
+
+		j.cookies[key] = &storedCookie{Url: u.String(), Cookie: &cookie}
+	}
+}
+
+func (j *cookieJar) Cookies(u *url.URL) []*http.Cookie {
+	return j.jar.Cookies(u)
+}
+
+func (j *cookieJar) Save(path string) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	now := time.Now()
+	stored := []*storedCookie{}
+
+	for key, item := range j.cookies {
+		if !item.Cookie.Expires.IsZero() && !item.Cookie.Expires.After(now) {
+			delete(j.cookies, key)
+			continue
+		}
+		stored = append(stored, item)
+	}
+
+	data, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+func cookieKey(u *url.URL, cookie *http.Cookie) string {
+	domain := cookie.Domain
+	if domain == "" {
+		domain = u.Hostname()
+	}
+
+	return domain + ";" + cookie.Path + ";" + cookie.Name
+}
@@ -0,0 +1,74 @@
+package page_loader
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCookieJar(t *testing.T) {
+	u := gotils.ResultOrPanic(url.Parse("http://demo.example/private"))
+
+	t.Run("Returns cookies set for the URL", func(t *testing.T) {
+		jar := NewCookieJar()
+
+		jar.SetCookies(u, []*http.Cookie{{Name: "session", Value: "42"}})
+		cookies := jar.Cookies(u)
+
+		assert.Equal(t, 1, len(cookies))
+		assert.Equal(t, "42", cookies[0].Value)
+	})
+
+	t.Run("Cookies survive saving and loading", func(t *testing.T) {
+		filePath := path.Join(os.Getenv("TMP_DIR"), "cookies.json")
+		defer os.Remove(filePath)
+		jar := NewCookieJar()
+		jar.SetCookies(u, []*http.Cookie{
+			{Name: "session", Value: "42"},
+			{Name: "remember", Value: "yes", MaxAge: 3600},
+		})
+
+		gotils.NilOrPanic(jar.Save(filePath))
+		loadedJar, err := LoadCookieJar(filePath)
+
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, jar.Cookies(u), loadedJar.Cookies(u))
+	})
+
+	t.Run("Expired cookies are not saved", func(t *testing.T) {
+		filePath := path.Join(os.Getenv("TMP_DIR"), "cookies.json")
+		defer os.Remove(filePath)
+		jar := NewCookieJar()
+		jar.SetCookies(u, []*http.Cookie{{Name: "session", Value: "42"}})
+		jar.SetCookies(u, []*http.Cookie{{Name: "session", Value: "", Expires: time.Unix(1, 0)}})
+
+		gotils.NilOrPanic(jar.Save(filePath))
+		loadedJar, err := LoadCookieJar(filePath)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(loadedJar.Cookies(u)))
+	})
+
+	t.Run("Returns empty jar if file not exists", func(t *testing.T) {
+		jar, err := LoadCookieJar("/var/this_file_does_not_exists")
+
+		assert.Nil(t, err)
+		assert.Equal(t, 0, len(jar.Cookies(u)))
+	})
+
+	t.Run("Returns error if file is invalid", func(t *testing.T) {
+		filePath := path.Join(os.Getenv("TMP_DIR"), "cookies.json")
+		defer os.Remove(filePath)
+		gotils.NilOrPanic(os.WriteFile(filePath, []byte("{"), 0600))
+
+		_, err := LoadCookieJar(filePath)
+
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,93 @@
+package page_loader
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/DAtek/gotils"
+)
+
+const (
+	ErrorNoStrategy = gotils.Error("NO_STRATEGY")
+	ErrorNoLoader   = gotils.Error("NO_LOADER")
+)
+
+type FallbackStrategy struct {
+	Name       string
+	Loader     IPageLoader
+	RewriteUrl func(url string) string
+}
+
+type fallbackPageLoader struct {
+	strategies []FallbackStrategy
+}
+
+func NewFallbackPageLoader(strategies ...FallbackStrategy) IPageLoader {
+	var primary IPageLoader
+	for _, strategy := range strategies {
+		if strategy.Loader != nil {
+			primary = strategy.Loader
+			break
+		}
+	}
+
+	resolved := make([]FallbackStrategy, len(strategies))
+	for i, strategy := range strategies {
+		if strategy.Loader == nil {
+			strategy.Loader = primary
+		}
+		resolved[i] = strategy
+	}
+
+	return &fallbackPageLoader{
+		strategies: resolved,
+	}
+}
+
+func (l *fallbackPageLoader) LoadPage(req *Request) (*Response, error) {
+	if len(l.strategies) == 0 {
+		return nil, ErrorNoStrategy
+	}
+
+	errs := []error{}
+	for _, strategy := range l.strategies {
+		strategyReq := req
+		if strategy.RewriteUrl != nil {
+			rewrittenReq := *req
+			rewrittenReq.Url = strategy.RewriteUrl(req.Url)
+			strategyReq = &rewrittenReq
+		}
+
+		if strategy.Loader == nil {
+			errs = append(errs, fmt.Errorf("%s: %w", strategy.Name, ErrorNoLoader))
+			continue
+		}
+
+		resp, err := strategy.Loader.LoadPage(strategyReq)
+		if err == nil && resp == nil {
+			err = ErrorEmptyResponse
+		}
+
+		if err == nil {
+			resp.Strategy = strategy.Name
+			return resp, nil
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", strategy.Name, err))
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+func ReplaceHost(host string) func(url string) string {
+	return func(rawUrl string) string {
+		u, err := url.Parse(rawUrl)
+		if err != nil {
+			return rawUrl
+		}
+
+		u.Host = host
+		return u.String()
+	}
+}
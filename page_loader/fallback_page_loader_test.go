@@ -0,0 +1,114 @@
+package page_loader
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFallbackPageLoader(t *testing.T) {
+	newLoader := func(allowedUrl string) IPageLoader {
+		return &MockPageLoader{
+			LoadPage_: func(req *Request) (*Response, error) {
+				if req.Url != allowedUrl {
+					return nil, errors.New("BLOCKED")
+				}
+				return &Response{Body: req.Url, StatusCode: http.StatusOK}, nil
+			},
+		}
+	}
+
+	t.Run("Returns page of the first successful strategy", func(t *testing.T) {
+		loader := NewFallbackPageLoader(
+			FallbackStrategy{Name: "desktop", Loader: newLoader("")},
+			FallbackStrategy{Name: "mobile", Loader: newLoader("http://demo.example/1")},
+			FallbackStrategy{Name: "other", Loader: newLoader("http://demo.example/1")},
+		)
+
+		res, err := loader.LoadPage(NewRequest("http://demo.example/1"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "http://demo.example/1", res.Body)
+		assert.Equal(t, "mobile", res.Strategy)
+	})
+
+	t.Run("Rewrites the URL of the request", func(t *testing.T) {
+		loader := NewFallbackPageLoader(
+			FallbackStrategy{Name: "origin", Loader: newLoader("")},
+			FallbackStrategy{
+				Name:       "mirror",
+				Loader:     newLoader("http://mirror.example/1?a=b"),
+				RewriteUrl: ReplaceHost("mirror.example"),
+			},
+		)
+		req := NewRequest("http://demo.example/1?a=b")
+
+		res, err := loader.LoadPage(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "mirror", res.Strategy)
+		assert.Equal(t, "http://demo.example/1?a=b", req.Url)
+	})
+
+	t.Run("Rewrites the URL with the primary loader if strategy has no loader", func(t *testing.T) {
+		loader := NewFallbackPageLoader(
+			FallbackStrategy{Name: "origin", Loader: newLoader("http://mirror.example/1")},
+			FallbackStrategy{Name: "mirror", RewriteUrl: ReplaceHost("mirror.example")},
+		)
+
+		res, err := loader.LoadPage(NewRequest("http://demo.example/1"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "mirror", res.Strategy)
+		assert.Equal(t, "http://mirror.example/1", res.Body)
+	})
+
+	t.Run("Returns error if no strategy has a loader", func(t *testing.T) {
+		loader := NewFallbackPageLoader(FallbackStrategy{Name: "mirror", RewriteUrl: ReplaceHost("mirror.example")})
+
+		_, err := loader.LoadPage(NewRequest("http://demo.example/1"))
+
+		assert.ErrorIs(t, err, ErrorNoLoader)
+	})
+
+	t.Run("Falls back if strategy returns no response", func(t *testing.T) {
+		loader := NewFallbackPageLoader(
+			FallbackStrategy{Name: "empty", Loader: &MockPageLoader{
+				LoadPage_: func(req *Request) (*Response, error) { return nil, nil },
+			}},
+			FallbackStrategy{Name: "mobile", Loader: newLoader("http://demo.example/1")},
+		)
+
+		res, err := loader.LoadPage(NewRequest("http://demo.example/1"))
+		_, emptyErr := NewFallbackPageLoader(FallbackStrategy{Name: "empty", Loader: &MockPageLoader{
+			LoadPage_: func(req *Request) (*Response, error) { return nil, nil },
+		}}).LoadPage(NewRequest("http://demo.example/1"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "mobile", res.Strategy)
+		assert.ErrorIs(t, emptyErr, ErrorEmptyResponse)
+	})
+
+	t.Run("Returns errors of all strategies", func(t *testing.T) {
+		loader := NewFallbackPageLoader(
+			FallbackStrategy{Name: "desktop", Loader: newLoader("")},
+			FallbackStrategy{Name: "mobile", Loader: newLoader("")},
+		)
+
+		_, err := loader.LoadPage(NewRequest("http://demo.example/1"))
+
+		assert.EqualError(t, err, "desktop: BLOCKED\nmobile: BLOCKED")
+	})
+
+	t.Run("Returns error without strategies", func(t *testing.T) {
+		_, err := NewFallbackPageLoader().LoadPage(NewRequest("http://demo.example/1"))
+
+		assert.Equal(t, ErrorNoStrategy, err)
+	})
+
+	t.Run("Replace host leaves invalid URL untouched", func(t *testing.T) {
+		assert.Equal(t, "http://[::1", ReplaceHost("mirror.example")("http://[::1"))
+	})
+}
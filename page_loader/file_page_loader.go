@@ -0,0 +1,85 @@
+package page_loader
+
+import (
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/DAtek/gotils"
+)
+
+const ErrorUnsupportedUrl = gotils.Error("UNSUPPORTED_URL")
+const ErrorUnsupportedMethod = gotils.Error("UNSUPPORTED_METHOD")
+
+type filePageLoader struct {
+	urlPrefix string
+	dir       string
+}
+
+func NewFilePageLoader(urlPrefix string, dir string) IPageLoader {
+	return &filePageLoader{
+		urlPrefix: urlPrefix,
+		dir:       dir,
+	}
+}
+
+func (loader *filePageLoader) LoadPage(req *Request) (*Response, error) {
+	if req.method() != http.MethodGet {
+		return nil, ErrorUnsupportedMethod
+	}
+
+	filePath, err := loader.getFilePath(req.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	if info.IsDir() {
+		filePath = filepath.Join(filePath, "index.html")
+	}
+
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	if contentType := mime.TypeByExtension(filepath.Ext(filePath)); contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+
+	return &Response{
+		Body:       string(content),
+		StatusCode: http.StatusOK,
+		Header:     header,
+	}, nil
+}
+
+func (loader *filePageLoader) getFilePath(rawUrl string) (string, error) {
+	if strings.HasPrefix(rawUrl, "file://") {
+		u, err := url.Parse(rawUrl)
+		if err != nil {
+			return "", err
+		}
+		return filepath.FromSlash(u.Path), nil
+	}
+
+	if loader.urlPrefix == "" || !strings.HasPrefix(rawUrl, loader.urlPrefix) {
+		return "", ErrorUnsupportedUrl
+	}
+
+	u, err := url.Parse("/" + strings.TrimPrefix(rawUrl, loader.urlPrefix))
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(loader.dir, filepath.FromSlash(path.Clean(u.Path))), nil
+}
@@ -0,0 +1,105 @@
+package page_loader
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilePageLoader(t *testing.T) {
+	siteDir := path.Join(os.Getenv("TMP_DIR"), "site")
+	urlPrefix := "http://demo.example/"
+
+	createSite := func() {
+		gotils.NilOrPanic(os.MkdirAll(path.Join(siteDir, "blog"), 0755))
+		gotils.NilOrPanic(os.WriteFile(path.Join(siteDir, "index.html"), []byte("home"), 0644))
+		gotils.NilOrPanic(os.WriteFile(path.Join(siteDir, "blog", "index.html"), []byte("blog"), 0644))
+		gotils.NilOrPanic(os.WriteFile(path.Join(siteDir, "blog", "post.html"), []byte("post"), 0644))
+	}
+
+	deleteSite := func() {
+		gotils.NilOrPanic(os.RemoveAll(siteDir))
+	}
+
+	t.Run("Loads file mapped from URL prefix", func(t *testing.T) {
+		createSite()
+		defer deleteSite()
+		loader := NewFilePageLoader(urlPrefix, siteDir)
+
+		res, err := loader.LoadPage(NewRequest(urlPrefix + "blog/post.html?page=1#top"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "post", res.Body)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/html; charset=utf-8", res.Header.Get("Content-Type"))
+	})
+
+	t.Run("Resolves index.html of directories", func(t *testing.T) {
+		createSite()
+		defer deleteSite()
+		loader := NewFilePageLoader(urlPrefix, siteDir)
+
+		home, homeErr := loader.LoadPage(NewRequest(urlPrefix))
+		blog, blogErr := loader.LoadPage(NewRequest(urlPrefix + "blog/"))
+
+		assert.Nil(t, homeErr)
+		assert.Nil(t, blogErr)
+		assert.Equal(t, "home", home.Body)
+		assert.Equal(t, "blog", blog.Body)
+	})
+
+	t.Run("Does not serve files outside of the directory", func(t *testing.T) {
+		createSite()
+		defer deleteSite()
+		gotils.NilOrPanic(os.WriteFile(path.Join(siteDir, "..", "secret.html"), []byte("secret"), 0644))
+		defer os.Remove(path.Join(siteDir, "..", "secret.html"))
+		loader := NewFilePageLoader(urlPrefix, siteDir)
+
+		_, err := loader.LoadPage(NewRequest(urlPrefix + "../secret.html"))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Loads file URL", func(t *testing.T) {
+		createSite()
+		defer deleteSite()
+		loader := NewFilePageLoader("", "")
+		absDir := gotils.ResultOrPanic(filepath.Abs(siteDir))
+
+		res, err := loader.LoadPage(NewRequest("file://" + path.Join(absDir, "blog", "post.html")))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "post", res.Body)
+	})
+
+	t.Run("Returns error if file not exists", func(t *testing.T) {
+		createSite()
+		defer deleteSite()
+		loader := NewFilePageLoader(urlPrefix, siteDir)
+
+		_, err := loader.LoadPage(NewRequest(urlPrefix + "missing.html"))
+
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("Returns error if URL is not mapped", func(t *testing.T) {
+		loader := NewFilePageLoader(urlPrefix, siteDir)
+
+		_, err := loader.LoadPage(NewRequest("http://other.example/"))
+
+		assert.Equal(t, ErrorUnsupportedUrl, err)
+	})
+
+	t.Run("Returns error if method is not GET", func(t *testing.T) {
+		loader := NewFilePageLoader(urlPrefix, siteDir)
+
+		_, err := loader.LoadPage(NewPostRequest(urlPrefix, "text/plain", ""))
+
+		assert.Equal(t, ErrorUnsupportedMethod, err)
+	})
+}
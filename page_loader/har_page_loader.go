@@ -0,0 +1,259 @@
+package page_loader
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/DAtek/gotils"
+)
+
+const ErrorNotRecorded = gotils.Error("NOT_RECORDED")
+
+type IRecordingPageLoader interface {
+	IPageLoader
+	Save(path string) error
+}
+
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string      `json:"version"`
+	Creator harCreator  `json:"creator"`
+	Entries []*harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	Url         string         `json:"url"`
+	HttpVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HttpVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectUrl string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+type recordingPageLoader struct {
+	loader  IPageLoader
+	mutex   *sync.Mutex
+	entries []*harEntry
+}
+
+func NewRecordingPageLoader(loader IPageLoader) IRecordingPageLoader {
+	return &recordingPageLoader{
+		loader:  loader,
+		mutex:   &sync.Mutex{},
+		entries: []*harEntry{},
+	}
+}
+
+func (r *recordingPageLoader) LoadPage(req *Request) (*Response, error) {
+	started := time.Now()
+	resp, err := r.loader.LoadPage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	elapsed := float64(time.Since(started).Milliseconds())
+	entry := &harEntry{
+		StartedDateTime: started,
+		Time:            elapsed,
+		Request:         newHarRequest(req),
+		Response:        newHarResponse(resp),
+		Timings:         harTimings{Wait: elapsed},
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.entries = append(r.entries, entry)
+	return resp, nil
+}
+
+func (r *recordingPageLoader) Save(path string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	data, err := json.MarshalIndent(&harFile{
+		Log: harLog{
+			Version: "1.2",
+			Creator: harCreator{Name: "grawler"},
+			Entries: r.entries,
+		},
+	}, "", "  ")
+
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0644)
+}
+
+type replayPageLoader struct {
+	responses map[string]*harResponse
+}
+
+func NewReplayPageLoader(path string) (IPageLoader, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	har := &harFile{}
+	if err := json.Unmarshal(data, har); err != nil {
+		return nil, err
+	}
+
+	responses := map[string]*harResponse{}
+	for _, entry := range har.Log.Entries {
+		req := &Request{Method: entry.Request.Method, Url: entry.Request.Url}
+		if entry.Request.PostData != nil {
+			req.Body = entry.Request.PostData.Text
+		}
+		responses[req.Fingerprint()] = &entry.Response
+	}
+
+	return &replayPageLoader{responses: responses}, nil
+}
+
+func (r *replayPageLoader) LoadPage(req *Request) (*Response, error) {
+	harResp, ok := r.responses[req.Fingerprint()]
+	if !ok {
+		return nil, ErrorNotRecorded
+	}
+
+	header := http.Header{}
+	for _, item := range harResp.Headers {
+		header.Add(item.Name, item.Value)
+	}
+
+	body := harResp.Content.Text
+	if harResp.Content.Encoding == "base64" {
+		decoded, err := base64.StdEncoding.DecodeString(body)
+		if err != nil {
+			return nil, err
+		}
+		body = string(decoded)
+	}
+
+	return &Response{
+		Body:       body,
+		StatusCode: harResp.Status,
+		Header:     header,
+	}, nil
+}
+
+func newHarRequest(req *Request) harRequest {
+	harReq := harRequest{
+		Method:      req.method(),
+		Url:         req.Url,
+		HttpVersion: "HTTP/1.1",
+		Headers:     newHarHeaders(req.Header),
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(req.Body),
+	}
+
+	if req.Body != "" {
+		harReq.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     req.Body,
+		}
+	}
+
+	return harReq
+}
+
+func newHarResponse(resp *Response) harResponse {
+	content := harContent{
+		Size:     len(resp.Body),
+		MimeType: resp.Header.Get("Content-Type"),
+		Text:     resp.Body,
+	}
+
+	if !utf8.ValidString(resp.Body) {
+		content.Text = base64.StdEncoding.EncodeToString([]byte(resp.Body))
+		content.Encoding = "base64"
+	}
+
+	return harResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HttpVersion: "HTTP/1.1",
+		Headers:     newHarHeaders(resp.Header),
+		Content:     content,
+		HeadersSize: -1,
+		BodySize:    len(resp.Body),
+	}
+}
+
+func newHarHeaders(header http.Header) []harNameValue {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	result := []harNameValue{}
+	for _, name := range names {
+		for _, value := range header[name] {
+			result = append(result, harNameValue{Name: name, Value: value})
+		}
+	}
+
+	return result
+}
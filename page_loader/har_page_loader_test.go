@@ -0,0 +1,101 @@
+package page_loader
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHarPageLoader(t *testing.T) {
+	harPath := path.Join(os.Getenv("TMP_DIR"), "crawl.har")
+
+	newLoader := func() IPageLoader {
+		return &MockPageLoader{
+			LoadPage_: func(req *Request) (*Response, error) {
+				if req.Url == "http://demo.example/error" {
+					return nil, errors.New("UNEXPECTED_ERROR")
+				}
+
+				header := http.Header{}
+				header.Set("Content-Type", "text/html")
+				body := req.Method + " " + req.Url + " " + req.Body
+				if req.Url == "http://demo.example/binary" {
+					body = string([]byte{0xff, 0xfe, 0x00})
+				}
+				return &Response{Body: body, StatusCode: http.StatusOK, Header: header}, nil
+			},
+		}
+	}
+
+	t.Run("Replays recorded responses", func(t *testing.T) {
+		defer os.Remove(harPath)
+		recorder := NewRecordingPageLoader(newLoader())
+		requests := []*Request{
+			NewRequest("http://demo.example/1"),
+			NewPostRequest("http://demo.example/search", "application/json", `{"page":2}`),
+			NewRequest("http://demo.example/binary"),
+		}
+
+		expected := []*Response{}
+		for _, req := range requests {
+			expected = append(expected, gotils.ResultOrPanic(recorder.LoadPage(req)))
+		}
+		gotils.NilOrPanic(recorder.Save(harPath))
+
+		replayer, err := NewReplayPageLoader(harPath)
+		assert.Nil(t, err)
+		for i, req := range requests {
+			res, err := replayer.LoadPage(req)
+			assert.Nil(t, err)
+			assert.Equal(t, expected[i], res)
+		}
+	})
+
+	t.Run("Recorder returns error of the page loader", func(t *testing.T) {
+		recorder := NewRecordingPageLoader(newLoader())
+
+		_, err := recorder.LoadPage(NewRequest("http://demo.example/error"))
+
+		assert.EqualError(t, err, "UNEXPECTED_ERROR")
+	})
+
+	t.Run("Replayer returns error for unknown requests", func(t *testing.T) {
+		defer os.Remove(harPath)
+		recorder := NewRecordingPageLoader(newLoader())
+		gotils.ResultOrPanic(recorder.LoadPage(NewRequest("http://demo.example/1")))
+		gotils.NilOrPanic(recorder.Save(harPath))
+		replayer := gotils.ResultOrPanic(NewReplayPageLoader(harPath))
+
+		_, err1 := replayer.LoadPage(NewRequest("http://demo.example/2"))
+		_, err2 := replayer.LoadPage(NewPostRequest("http://demo.example/1", "text/plain", "a"))
+
+		assert.Equal(t, ErrorNotRecorded, err1)
+		assert.Equal(t, ErrorNotRecorded, err2)
+	})
+
+	t.Run("Returns error if HAR file not exists", func(t *testing.T) {
+		_, err := NewReplayPageLoader("/var/this_file_does_not_exists")
+
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("Returns error if HAR file is invalid", func(t *testing.T) {
+		defer os.Remove(harPath)
+		gotils.NilOrPanic(os.WriteFile(harPath, []byte("{"), 0644))
+
+		_, err := NewReplayPageLoader(harPath)
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Returns error if saving fails", func(t *testing.T) {
+		recorder := NewRecordingPageLoader(newLoader())
+
+		assert.Error(t, recorder.Save("/var/this_directory_does_not_exists/crawl.har"))
+	})
+}
@@ -2,42 +2,141 @@ package page_loader
 
 import (
 	"bytes"
-	"errors"
 	"io"
 	"net/http"
-
-	"github.com/DAtek/gotils"
+	"sync"
 )
 
+type StatusError struct {
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return e.Status
+}
+
+type LoginFunc func(client *http.Client) (http.Header, error)
+
+type HttpPageLoaderOption func(loader *httpPageLoader)
+
+func WithCookieJar(jar http.CookieJar) HttpPageLoaderOption {
+	return func(loader *httpPageLoader) {
+		loader.client.Jar = jar
+	}
+}
+
+func WithLogin(login LoginFunc) HttpPageLoaderOption {
+	return func(loader *httpPageLoader) {
+		loader.login = login
+	}
+}
+
+func WithRequestDecorators(decorators ...RequestDecorator) HttpPageLoaderOption {
+	return func(loader *httpPageLoader) {
+		loader.decorators = append(loader.decorators, decorators...)
+	}
+}
+
 type httpPageLoader struct {
-	header http.Header
-	client *http.Client
+	header     http.Header
+	client     *http.Client
+	decorators []RequestDecorator
+	login      LoginFunc
+	loginMutex *sync.Mutex
+	loggedIn   bool
+	session    http.Header
 }
 
-func NewHttpPageLoader(header http.Header) IPageLoader {
-	return &httpPageLoader{
-		header: header,
-		client: &http.Client{},
+func NewHttpPageLoader(header http.Header, options ...HttpPageLoaderOption) IPageLoader {
+	loader := &httpPageLoader{
+		header:     header,
+		client:     &http.Client{},
+		loginMutex: &sync.Mutex{},
 	}
+
+	for _, option := range options {
+		option(loader)
+	}
+
+	if loader.login != nil && loader.client.Jar == nil {
+		loader.client.Jar = NewCookieJar()
+	}
+
+	return loader
 }
 
-func (loader *httpPageLoader) LoadPage(url string) (string, error) {
-	req := gotils.ResultOrPanic(http.NewRequest("GET", url, &bytes.Buffer{}))
+func (loader *httpPageLoader) LoadPage(req *Request) (*Response, error) {
+	if err := loader.ensureLoggedIn(); err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequest(req.method(), req.Url, bytes.NewBufferString(req.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq.Header = loader.header.Clone()
+	if httpReq.Header == nil {
+		httpReq.Header = http.Header{}
+	}
 
-	if loader.header != nil {
-		req.Header = loader.header
+	mergeHeader(httpReq.Header, loader.session)
+	mergeHeader(httpReq.Header, req.Header)
+
+	for _, decorate := range loader.decorators {
+		if err := decorate(httpReq, req); err != nil {
+			return nil, err
+		}
 	}
 
-	resp, respErr := loader.client.Do(req)
+	resp, respErr := loader.client.Do(httpReq)
 	if respErr != nil {
-		return "", respErr
+		return nil, respErr
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return &Response{StatusCode: resp.StatusCode, Header: resp.Header}, nil
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", errors.New(resp.Status)
+		return nil, &StatusError{StatusCode: resp.StatusCode, Status: resp.Status}
 	}
 
 	buf := &bytes.Buffer{}
 	io.Copy(buf, resp.Body)
-	return buf.String(), nil
+	return &Response{
+		Body:       buf.String(),
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}, nil
+}
+
+func (loader *httpPageLoader) ensureLoggedIn() error {
+	if loader.login == nil {
+		return nil
+	}
+
+	loader.loginMutex.Lock()
+	defer loader.loginMutex.Unlock()
+
+	if loader.loggedIn {
+		return nil
+	}
+
+	session, err := loader.login(loader.client)
+	if err != nil {
+		return err
+	}
+
+	loader.session = session
+	loader.loggedIn = true
+	return nil
+}
+
+func mergeHeader(dst, src http.Header) {
+	for key, values := range src {
+		dst[key] = append([]string(nil), values...)
+	}
 }
@@ -1,20 +1,26 @@
 package page_loader
 
 import (
+	"errors"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
 	"testing"
 	"time"
 
+	"github.com/DAtek/gotils"
 	"github.com/stretchr/testify/assert"
 )
 
 const (
-	addr        = "127.0.0.1:8000"
-	baseUrl     = "http://" + addr
-	headerKey   = "X-Custom-Header"
-	headerValue = "42"
+	addr         = "127.0.0.1:8000"
+	baseUrl      = "http://" + addr
+	headerKey    = "X-Custom-Header"
+	headerValue  = "42"
+	etag         = `"v1"`
+	lastModified = "Wed, 21 Oct 2015 07:28:00 GMT"
+	sessionId    = "1234"
 )
 
 func TestHttpPageLoader(t *testing.T) {
@@ -24,25 +30,205 @@ func TestHttpPageLoader(t *testing.T) {
 		stopServer <- nil
 	}()
 
-	// wait for server startup
-	time.Sleep(10 * time.Microsecond)
+	waitServerStartup()
 	t.Run("Loads page content with correct request header", func(t *testing.T) {
 		header := http.Header{}
 		header.Add(headerKey, headerValue)
 		loader := NewHttpPageLoader(header)
 
 		u, _ := url.JoinPath(baseUrl, "/ok")
-		res, err := loader.LoadPage(u)
+		res, err := loader.LoadPage(NewRequest(u))
 
 		assert.Nil(t, err)
-		assert.Equal(t, "hey", res)
+		assert.Equal(t, "hey", res.Body)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+	})
+
+	t.Run("Does not modify the shared header", func(t *testing.T) {
+		header := http.Header{}
+		header.Add(headerKey, headerValue)
+		loader := NewHttpPageLoader(header)
+
+		u, _ := url.JoinPath(baseUrl, "/ok")
+		req := NewRequest(u)
+		req.Header.Set("If-None-Match", etag)
+		_, err := loader.LoadPage(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.Header{headerKey: {headerValue}}, header)
+	})
+
+	t.Run("Returns validators of the page", func(t *testing.T) {
+		loader := NewHttpPageLoader(nil)
+
+		u, _ := url.JoinPath(baseUrl, "/conditional")
+		res, err := loader.LoadPage(NewRequest(u))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "fresh", res.Body)
+		assert.Equal(t, etag, res.Header.Get("ETag"))
+		assert.Equal(t, lastModified, res.Header.Get("Last-Modified"))
+	})
+
+	t.Run("Returns not modified response if ETag matches", func(t *testing.T) {
+		loader := NewHttpPageLoader(nil)
+
+		u, _ := url.JoinPath(baseUrl, "/conditional")
+		req := NewRequest(u)
+		req.Header.Set("If-None-Match", etag)
+		res, err := loader.LoadPage(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotModified, res.StatusCode)
+		assert.Equal(t, "", res.Body)
+	})
+
+	t.Run("Returns not modified response if page was not modified since", func(t *testing.T) {
+		loader := NewHttpPageLoader(nil)
+
+		u, _ := url.JoinPath(baseUrl, "/conditional")
+		req := NewRequest(u)
+		req.Header.Set("If-Modified-Since", lastModified)
+		res, err := loader.LoadPage(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusNotModified, res.StatusCode)
 	})
 
 	t.Run("Returns error if status code is not OK", func(t *testing.T) {
 		loader := NewHttpPageLoader(nil)
 
 		u, _ := url.JoinPath(baseUrl, "/not-ok")
-		_, err := loader.LoadPage(u)
+		_, err := loader.LoadPage(NewRequest(u))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Reuses session cookies of the login", func(t *testing.T) {
+		loginCount := 0
+		loader := NewHttpPageLoader(nil, WithLogin(func(client *http.Client) (http.Header, error) {
+			loginCount++
+			u, _ := url.JoinPath(baseUrl, "/login")
+			resp, err := client.PostForm(u, url.Values{"password": {sessionId}})
+			if err != nil {
+				return nil, err
+			}
+			resp.Body.Close()
+			return nil, nil
+		}))
+
+		u, _ := url.JoinPath(baseUrl, "/private")
+		res1, err1 := loader.LoadPage(NewRequest(u))
+		res2, err2 := loader.LoadPage(NewRequest(u))
+
+		assert.Nil(t, err1)
+		assert.Nil(t, err2)
+		assert.Equal(t, "secret", res1.Body)
+		assert.Equal(t, "secret", res2.Body)
+		assert.Equal(t, 1, loginCount)
+	})
+
+	t.Run("Sends header returned by the login", func(t *testing.T) {
+		loader := NewHttpPageLoader(nil, WithLogin(func(client *http.Client) (http.Header, error) {
+			return http.Header{"Authorization": {"Bearer " + sessionId}}, nil
+		}))
+
+		u, _ := url.JoinPath(baseUrl, "/private")
+		res, err := loader.LoadPage(NewRequest(u))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "secret", res.Body)
+	})
+
+	t.Run("Returns error if login fails", func(t *testing.T) {
+		loginErr := errors.New("UNEXPECTED_ERROR")
+		loader := NewHttpPageLoader(nil, WithLogin(func(client *http.Client) (http.Header, error) {
+			return nil, loginErr
+		}))
+
+		u, _ := url.JoinPath(baseUrl, "/private")
+		_, err := loader.LoadPage(NewRequest(u))
+
+		assert.Equal(t, loginErr, err)
+	})
+
+	t.Run("Retries login after failure", func(t *testing.T) {
+		loginCount := 0
+		loader := NewHttpPageLoader(nil, WithLogin(func(client *http.Client) (http.Header, error) {
+			loginCount++
+			if loginCount == 1 {
+				return nil, errors.New("UNEXPECTED_ERROR")
+			}
+			return http.Header{"Authorization": {"Bearer " + sessionId}}, nil
+		}))
+
+		u, _ := url.JoinPath(baseUrl, "/private")
+		_, err1 := loader.LoadPage(NewRequest(u))
+		res2, err2 := loader.LoadPage(NewRequest(u))
+		res3, err3 := loader.LoadPage(NewRequest(u))
+
+		assert.Error(t, err1)
+		assert.Nil(t, err2)
+		assert.Nil(t, err3)
+		assert.Equal(t, "secret", res2.Body)
+		assert.Equal(t, "secret", res3.Body)
+		assert.Equal(t, 2, loginCount)
+	})
+
+	t.Run("Uses the given cookie jar", func(t *testing.T) {
+		jar := NewCookieJar()
+		u, _ := url.JoinPath(baseUrl, "/private")
+		jar.SetCookies(gotils.ResultOrPanic(url.Parse(u)), []*http.Cookie{{Name: "session", Value: sessionId}})
+		loader := NewHttpPageLoader(nil, WithCookieJar(jar))
+
+		res, err := loader.LoadPage(NewRequest(u))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "secret", res.Body)
+	})
+
+	t.Run("Applies request decorators", func(t *testing.T) {
+		loader := NewHttpPageLoader(
+			nil,
+			WithRequestDecorators(RefererDecorator, NewUserAgentRotator("grawler")),
+		)
+
+		u, _ := url.JoinPath(baseUrl, "/headers")
+		req := NewRequest(u)
+		req.Referer = baseUrl
+		res, err := loader.LoadPage(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, "grawler|"+baseUrl, res.Body)
+	})
+
+	t.Run("Returns error if a request decorator fails", func(t *testing.T) {
+		decoratorErr := errors.New("UNEXPECTED_ERROR")
+		loader := NewHttpPageLoader(nil, WithRequestDecorators(func(httpReq *http.Request, req *Request) error {
+			return decoratorErr
+		}))
+
+		u, _ := url.JoinPath(baseUrl, "/headers")
+		_, err := loader.LoadPage(NewRequest(u))
+
+		assert.Equal(t, decoratorErr, err)
+	})
+
+	t.Run("Sends method and body of the request", func(t *testing.T) {
+		loader := NewHttpPageLoader(nil)
+
+		u, _ := url.JoinPath(baseUrl, "/echo")
+		res, err := loader.LoadPage(NewPostRequest(u, "application/json", `{"page":2}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, `POST application/json {"page":2}`, res.Body)
+	})
+
+	t.Run("Returns error if method is invalid", func(t *testing.T) {
+		loader := NewHttpPageLoader(nil)
+
+		u, _ := url.JoinPath(baseUrl, "/echo")
+		_, err := loader.LoadPage(&Request{Method: "GET POST", Url: u})
 
 		assert.Error(t, err)
 	})
@@ -50,7 +236,7 @@ func TestHttpPageLoader(t *testing.T) {
 	t.Run("Returns error if URL is invalid", func(t *testing.T) {
 		loader := NewHttpPageLoader(nil)
 
-		_, err := loader.LoadPage("invalid url")
+		_, err := loader.LoadPage(NewRequest("invalid url"))
 
 		assert.ErrorContains(t, err, "unsupported protocol scheme")
 	})
@@ -69,6 +255,48 @@ func runDemoServer(stopServer chan any) {
 		io.WriteString(w, "hey")
 	})
 
+	mux.HandleFunc("/conditional", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == etag || r.Header.Get("If-Modified-Since") == lastModified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", lastModified)
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, "fresh")
+	})
+
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.FormValue("password") != sessionId {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: sessionId, Path: "/"})
+		w.WriteHeader(http.StatusOK)
+	})
+
+	mux.HandleFunc("/private", func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie("session")
+		loggedIn := err == nil && cookie.Value == sessionId
+		if !loggedIn && r.Header.Get("Authorization") != "Bearer "+sessionId {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, "secret")
+	})
+
+	mux.HandleFunc("/headers", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, r.Header.Get("User-Agent")+"|"+r.Header.Get("Referer"))
+	})
+
+	mux.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusOK)
+		io.WriteString(w, r.Method+" "+r.Header.Get("Content-Type")+" "+string(body))
+	})
+
 	mux.HandleFunc("/mnot-ok", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 	})
@@ -89,3 +317,13 @@ func runDemoServer(stopServer chan any) {
 		panic(err)
 	}
 }
+
+func waitServerStartup() {
+	for i := 0; i < 100; i++ {
+		if conn, err := net.Dial("tcp", addr); err == nil {
+			conn.Close()
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
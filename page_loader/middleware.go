@@ -0,0 +1,178 @@
+package page_loader
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DAtek/gotils"
+)
+
+type Middleware func(IPageLoader) IPageLoader
+
+type PageLoaderFunc func(req *Request) (*Response, error)
+
+func (f PageLoaderFunc) LoadPage(req *Request) (*Response, error) {
+	return f(req)
+}
+
+func Chain(loader IPageLoader, middlewares ...Middleware) IPageLoader {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		loader = middlewares[i](loader)
+	}
+
+	return loader
+}
+
+func LoggingMiddleware(logger *gotils.Logger) Middleware {
+	return func(next IPageLoader) IPageLoader {
+		return PageLoaderFunc(func(req *Request) (*Response, error) {
+			started := time.Now()
+			resp, err := next.LoadPage(req)
+
+			if err != nil {
+				logger.Warning("pageLoader | %s %s failed after %s Error: %s", req.method(), req.Url, time.Since(started), err)
+				return resp, err
+			}
+
+			if resp == nil {
+				logger.Warning("pageLoader | %s %s returned no response after %s", req.method(), req.Url, time.Since(started))
+				return resp, err
+			}
+
+			logger.Debug("pageLoader | %s %s %d in %s", req.method(), req.Url, resp.StatusCode, time.Since(started))
+			return resp, err
+		})
+	}
+}
+
+type MetricsRecorder func(req *Request, resp *Response, err error, duration time.Duration)
+
+type PageLoaderMetrics struct {
+	Requests atomic.Int64
+	Failures atomic.Int64
+	Bytes    atomic.Int64
+	Duration atomic.Int64
+}
+
+func (m *PageLoaderMetrics) Record(req *Request, resp *Response, err error, duration time.Duration) {
+	m.Requests.Add(1)
+	m.Duration.Add(int64(duration))
+
+	if err != nil {
+		m.Failures.Add(1)
+		return
+	}
+
+	if resp == nil {
+		return
+	}
+
+	m.Bytes.Add(int64(len(resp.Body)))
+}
+
+func MetricsMiddleware(record MetricsRecorder) Middleware {
+	return func(next IPageLoader) IPageLoader {
+		return PageLoaderFunc(func(req *Request) (*Response, error) {
+			started := time.Now()
+			resp, err := next.LoadPage(req)
+			record(req, resp, err, time.Since(started))
+			return resp, err
+		})
+	}
+}
+
+type RetryOption func(config *retryConfig)
+
+func RetryNonIdempotent() RetryOption {
+	return func(config *retryConfig) {
+		config.nonIdempotent = true
+	}
+}
+
+type retryConfig struct {
+	nonIdempotent bool
+}
+
+func RetryMiddleware(attempts int, backoff time.Duration, options ...RetryOption) Middleware {
+	attempts = max(attempts, 1)
+	config := &retryConfig{}
+	for _, option := range options {
+		option(config)
+	}
+
+	return func(next IPageLoader) IPageLoader {
+		return PageLoaderFunc(func(req *Request) (*Response, error) {
+			if !config.nonIdempotent && !isIdempotent(req.method()) {
+				return next.LoadPage(req)
+			}
+
+			var resp *Response
+			var err error
+			delay := backoff
+
+			for i := 0; i < attempts; i++ {
+				if i > 0 {
+					time.Sleep(delay)
+					delay *= 2
+				}
+
+				resp, err = next.LoadPage(req)
+				if err == nil || errors.Is(err, ErrorCircuitOpen) || !isServerFailure(err) {
+					return resp, err
+				}
+			}
+
+			return resp, err
+		})
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return false
+}
+
+func RateLimitMiddleware(interval time.Duration) Middleware {
+	return func(next IPageLoader) IPageLoader {
+		mutex := &sync.Mutex{}
+		nextSlots := map[string]time.Time{}
+
+		return PageLoaderFunc(func(req *Request) (*Response, error) {
+			host := GetHost(req.Url)
+			now := time.Now()
+
+			mutex.Lock()
+			slot := nextSlots[host]
+			if slot.Before(now) {
+				slot = now
+			}
+			nextSlots[host] = slot.Add(interval)
+			mutex.Unlock()
+
+			time.Sleep(slot.Sub(now))
+			return next.LoadPage(req)
+		})
+	}
+}
+
+func HeaderMiddleware(header http.Header) Middleware {
+	return func(next IPageLoader) IPageLoader {
+		return PageLoaderFunc(func(req *Request) (*Response, error) {
+			decoratedReq := *req
+			decoratedReq.Header = req.Header.Clone()
+			if decoratedReq.Header == nil {
+				decoratedReq.Header = http.Header{}
+			}
+
+			mergeHeader(decoratedReq.Header, header)
+			return next.LoadPage(&decoratedReq)
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package page_loader
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	okLoader := &MockPageLoader{
+		LoadPage_: func(req *Request) (*Response, error) {
+			return &Response{Body: req.Header.Get(headerKey), StatusCode: http.StatusOK}, nil
+		},
+	}
+
+	t.Run("Chain applies middlewares in order", func(t *testing.T) {
+		calls := []string{}
+		newMiddleware := func(name string) Middleware {
+			return func(next IPageLoader) IPageLoader {
+				return PageLoaderFunc(func(req *Request) (*Response, error) {
+					calls = append(calls, name)
+					return next.LoadPage(req)
+				})
+			}
+		}
+
+		loader := Chain(okLoader, newMiddleware("a"), newMiddleware("b"))
+		_, err := loader.LoadPage(NewRequest("http://demo.example"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"a", "b"}, calls)
+	})
+
+	t.Run("Chain without middlewares returns the page loader", func(t *testing.T) {
+		assert.Equal(t, IPageLoader(okLoader), Chain(okLoader))
+	})
+
+	t.Run("Logging middleware logs requests and failures", func(t *testing.T) {
+		outBuf := &bytes.Buffer{}
+		logger := gotils.NewLogger(gotils.LogLevelDebug, outBuf, &bytes.Buffer{})
+		failingLoader := &MockPageLoader{
+			LoadPage_: func(req *Request) (*Response, error) {
+				return nil, errors.New("UNEXPECTED_ERROR")
+			},
+		}
+
+		Chain(okLoader, LoggingMiddleware(logger)).LoadPage(NewRequest("http://demo.example/ok"))
+		Chain(failingLoader, LoggingMiddleware(logger)).LoadPage(NewRequest("http://demo.example/fail"))
+
+		assert.True(t, strings.Contains(outBuf.String(), "GET http://demo.example/ok 200"))
+		assert.True(t, strings.Contains(outBuf.String(), "UNEXPECTED_ERROR"))
+	})
+
+	t.Run("Metrics middleware records requests", func(t *testing.T) {
+		metrics := &PageLoaderMetrics{}
+		fail := false
+		loader := Chain(&MockPageLoader{
+			LoadPage_: func(req *Request) (*Response, error) {
+				if fail {
+					return nil, errors.New("UNEXPECTED_ERROR")
+				}
+				return &Response{Body: "hey"}, nil
+			},
+		}, MetricsMiddleware(metrics.Record))
+
+		loader.LoadPage(NewRequest("http://demo.example"))
+		fail = true
+		loader.LoadPage(NewRequest("http://demo.example"))
+
+		assert.Equal(t, int64(2), metrics.Requests.Load())
+		assert.Equal(t, int64(1), metrics.Failures.Load())
+		assert.Equal(t, int64(3), metrics.Bytes.Load())
+	})
+
+	t.Run("Retry middleware retries failed requests", func(t *testing.T) {
+		calls := 0
+		loader := Chain(&MockPageLoader{
+			LoadPage_: func(req *Request) (*Response, error) {
+				calls++
+				if calls < 3 {
+					return nil, &StatusError{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway"}
+				}
+				return &Response{StatusCode: http.StatusOK}, nil
+			},
+		}, RetryMiddleware(3, time.Millisecond))
+
+		res, err := loader.LoadPage(NewRequest("http://demo.example"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("Retry middleware returns the last error", func(t *testing.T) {
+		calls := 0
+		loader := Chain(&MockPageLoader{
+			LoadPage_: func(req *Request) (*Response, error) {
+				calls++
+				return nil, errors.New("UNEXPECTED_ERROR")
+			},
+		}, RetryMiddleware(2, time.Millisecond))
+
+		_, err := loader.LoadPage(NewRequest("http://demo.example"))
+
+		assert.EqualError(t, err, "UNEXPECTED_ERROR")
+		assert.Equal(t, 2, calls)
+	})
+
+	t.Run("Retry middleware does not retry client errors", func(t *testing.T) {
+		calls := 0
+		loader := Chain(&MockPageLoader{
+			LoadPage_: func(req *Request) (*Response, error) {
+				calls++
+				return nil, &StatusError{StatusCode: http.StatusNotFound, Status: "404 Not Found"}
+			},
+		}, RetryMiddleware(3, time.Millisecond))
+
+		_, err := loader.LoadPage(NewRequest("http://demo.example"))
+
+		assert.EqualError(t, err, "404 Not Found")
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("Retry middleware does not retry non-idempotent requests", func(t *testing.T) {
+		calls := 0
+		failingLoader := &MockPageLoader{
+			LoadPage_: func(req *Request) (*Response, error) {
+				calls++
+				return nil, &StatusError{StatusCode: http.StatusBadGateway, Status: "502 Bad Gateway"}
+			},
+		}
+
+		_, err := Chain(failingLoader, RetryMiddleware(3, time.Millisecond)).LoadPage(NewPostRequest("http://demo.example", "application/json", "{}"))
+		postCalls := calls
+		calls = 0
+		Chain(failingLoader, RetryMiddleware(3, time.Millisecond, RetryNonIdempotent())).LoadPage(NewPostRequest("http://demo.example", "application/json", "{}"))
+
+		assert.EqualError(t, err, "502 Bad Gateway")
+		assert.Equal(t, 1, postCalls)
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("Logging and metrics middlewares accept missing response", func(t *testing.T) {
+		metrics := &PageLoaderMetrics{}
+		outBuf := &bytes.Buffer{}
+		logger := gotils.NewLogger(gotils.LogLevelDebug, outBuf, &bytes.Buffer{})
+		emptyLoader := &MockPageLoader{
+			LoadPage_: func(req *Request) (*Response, error) {
+				return nil, nil
+			},
+		}
+
+		res, err := Chain(emptyLoader, LoggingMiddleware(logger), MetricsMiddleware(metrics.Record)).LoadPage(NewRequest("http://demo.example"))
+
+		assert.Nil(t, res)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), metrics.Requests.Load())
+		assert.Equal(t, int64(0), metrics.Bytes.Load())
+		assert.True(t, strings.Contains(outBuf.String(), "returned no response"))
+	})
+
+	t.Run("Rate limit middleware spaces requests to the same host", func(t *testing.T) {
+		interval := 20 * time.Millisecond
+		loader := Chain(okLoader, RateLimitMiddleware(interval))
+		started := time.Now()
+
+		loader.LoadPage(NewRequest("http://demo.example/1"))
+		loader.LoadPage(NewRequest("http://other.example/1"))
+		loader.LoadPage(NewRequest("http://demo.example/2"))
+		loader.LoadPage(NewRequest("http://demo.example/3"))
+
+		assert.GreaterOrEqual(t, time.Since(started), 2*interval)
+	})
+
+	t.Run("Header middleware injects header without modifying the request", func(t *testing.T) {
+		loader := Chain(okLoader, HeaderMiddleware(http.Header{headerKey: {headerValue}}))
+		req := NewRequest("http://demo.example")
+
+		res, err := loader.LoadPage(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, headerValue, res.Body)
+		assert.Equal(t, http.Header{}, req.Header)
+	})
+}
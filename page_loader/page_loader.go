@@ -1,13 +1,73 @@
 package page_loader
 
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+
+	"github.com/DAtek/gotils"
+)
+
+const ErrorEmptyResponse = gotils.Error("EMPTY_RESPONSE")
+
+type Request struct {
+	Method  string
+	Url     string
+	Header  http.Header
+	Body    string
+	Referer string
+}
+
+func NewRequest(url string) *Request {
+	return &Request{
+		Method: http.MethodGet,
+		Url:    url,
+		Header: http.Header{},
+	}
+}
+
+func NewPostRequest(url string, contentType string, body string) *Request {
+	req := &Request{
+		Method: http.MethodPost,
+		Url:    url,
+		Header: http.Header{},
+		Body:   body,
+	}
+	req.Header.Set("Content-Type", contentType)
+	return req
+}
+
+func (r *Request) Fingerprint() string {
+	if r.method() == http.MethodGet && r.Body == "" {
+		return r.Url
+	}
+
+	return fmt.Sprintf("%s %s %x", r.method(), r.Url, sha256.Sum256([]byte(r.Body)))
+}
+
+func (r *Request) method() string {
+	if r.Method == "" {
+		return http.MethodGet
+	}
+
+	return r.Method
+}
+
+type Response struct {
+	Body       string
+	StatusCode int
+	Header     http.Header
+	Strategy   string
+}
+
 type IPageLoader interface {
-	LoadPage(url string) (string, error)
+	LoadPage(req *Request) (*Response, error)
 }
 
 type MockPageLoader struct {
-	LoadPage_ func(url string) (string, error)
+	LoadPage_ func(req *Request) (*Response, error)
 }
 
-func (m *MockPageLoader) LoadPage(url string) (string, error) {
-	return m.LoadPage_(url)
+func (m *MockPageLoader) LoadPage(req *Request) (*Response, error) {
+	return m.LoadPage_(req)
 }
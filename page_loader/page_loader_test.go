@@ -16,14 +16,54 @@ func TestMockPageLoader(t *testing.T) {
 		pageLoader := newMockPageLoader().(*MockPageLoader)
 		expectedContent := "content"
 
-		pageLoader.LoadPage_ = func(url string) (string, error) {
-			return expectedContent, nil
+		pageLoader.LoadPage_ = func(req *Request) (*Response, error) {
+			return &Response{Body: expectedContent}, nil
 		}
 
-		result, err := pageLoader.LoadPage("")
+		result, err := pageLoader.LoadPage(NewRequest(""))
 
 		assert.Nil(t, err)
-		assert.Equal(t, expectedContent, result)
+		assert.Equal(t, expectedContent, result.Body)
 	})
 
 }
+
+func TestRequestFingerprint(t *testing.T) {
+	t.Run("Fingerprint of GET request is the URL", func(t *testing.T) {
+		u := "http://demo.example/1"
+
+		assert.Equal(t, u, NewRequest(u).Fingerprint())
+	})
+
+	t.Run("Fingerprint of request without method is the URL", func(t *testing.T) {
+		u := "http://demo.example/1"
+
+		assert.Equal(t, u, (&Request{Url: u}).Fingerprint())
+	})
+
+	t.Run("Fingerprints of requests with the same body are equal", func(t *testing.T) {
+		u := "http://demo.example/search"
+
+		assert.Equal(
+			t,
+			NewPostRequest(u, "application/json", `{"page":2}`).Fingerprint(),
+			NewPostRequest(u, "application/json", `{"page":2}`).Fingerprint(),
+		)
+	})
+
+	t.Run("Fingerprints of requests with different bodies differ", func(t *testing.T) {
+		u := "http://demo.example/search"
+
+		assert.NotEqual(
+			t,
+			NewPostRequest(u, "application/json", `{"page":2}`).Fingerprint(),
+			NewPostRequest(u, "application/json", `{"page":3}`).Fingerprint(),
+		)
+	})
+
+	t.Run("Fingerprint of POST request differs from the URL", func(t *testing.T) {
+		u := "http://demo.example/search"
+
+		assert.NotEqual(t, u, NewPostRequest(u, "application/json", "").Fingerprint())
+	})
+}
@@ -0,0 +1,41 @@
+package page_loader
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+type RequestDecorator func(httpReq *http.Request, req *Request) error
+
+func RefererDecorator(httpReq *http.Request, req *Request) error {
+	if req.Referer != "" {
+		httpReq.Header.Set("Referer", req.Referer)
+	}
+
+	return nil
+}
+
+func NewHostHeaderDecorator(host string, header http.Header) RequestDecorator {
+	return func(httpReq *http.Request, req *Request) error {
+		if httpReq.URL.Hostname() != host {
+			return nil
+		}
+
+		mergeHeader(httpReq.Header, header)
+		return nil
+	}
+}
+
+func NewUserAgentRotator(userAgents ...string) RequestDecorator {
+	counter := &atomic.Uint64{}
+
+	return func(httpReq *http.Request, req *Request) error {
+		if len(userAgents) == 0 {
+			return nil
+		}
+
+		i := (counter.Add(1) - 1) % uint64(len(userAgents))
+		httpReq.Header.Set("User-Agent", userAgents[i])
+		return nil
+	}
+}
@@ -0,0 +1,72 @@
+package page_loader
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestDecorators(t *testing.T) {
+	newHttpRequest := func(u string) *http.Request {
+		return gotils.ResultOrPanic(http.NewRequest("GET", u, nil))
+	}
+
+	t.Run("Referer decorator sets the parent page", func(t *testing.T) {
+		req := NewRequest("http://demo.example/2")
+		req.Referer = "http://demo.example/1"
+		httpReq := newHttpRequest(req.Url)
+
+		gotils.NilOrPanic(RefererDecorator(httpReq, req))
+
+		assert.Equal(t, req.Referer, httpReq.Header.Get("Referer"))
+	})
+
+	t.Run("Referer decorator leaves header untouched without parent page", func(t *testing.T) {
+		req := NewRequest("http://demo.example/1")
+		httpReq := newHttpRequest(req.Url)
+
+		gotils.NilOrPanic(RefererDecorator(httpReq, req))
+
+		assert.Equal(t, http.Header{}, httpReq.Header)
+	})
+
+	t.Run("Host header decorator sets header only for its host", func(t *testing.T) {
+		decorate := NewHostHeaderDecorator("api.example", http.Header{"Authorization": {"Bearer 42"}})
+		apiReq := NewRequest("http://api.example/items")
+		apiHttpReq := newHttpRequest(apiReq.Url)
+		otherReq := NewRequest("http://demo.example/items")
+		otherHttpReq := newHttpRequest(otherReq.Url)
+
+		gotils.NilOrPanic(decorate(apiHttpReq, apiReq))
+		gotils.NilOrPanic(decorate(otherHttpReq, otherReq))
+
+		assert.Equal(t, "Bearer 42", apiHttpReq.Header.Get("Authorization"))
+		assert.Equal(t, "", otherHttpReq.Header.Get("Authorization"))
+	})
+
+	t.Run("User agent rotator rotates user agents", func(t *testing.T) {
+		decorate := NewUserAgentRotator("a", "b")
+		userAgents := []string{}
+
+		for i := 0; i < 3; i++ {
+			req := NewRequest("http://demo.example")
+			httpReq := newHttpRequest(req.Url)
+			gotils.NilOrPanic(decorate(httpReq, req))
+			userAgents = append(userAgents, httpReq.Header.Get("User-Agent"))
+		}
+
+		assert.Equal(t, []string{"a", "b", "a"}, userAgents)
+	})
+
+	t.Run("User agent rotator does nothing without user agents", func(t *testing.T) {
+		decorate := NewUserAgentRotator()
+		req := NewRequest("http://demo.example")
+		httpReq := newHttpRequest(req.Url)
+
+		gotils.NilOrPanic(decorate(httpReq, req))
+
+		assert.Equal(t, http.Header{}, httpReq.Header)
+	})
+}
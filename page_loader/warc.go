@@ -0,0 +1,282 @@
+package page_loader
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base32"
+	"fmt"
+	"io"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DAtek/gotils"
+)
+
+const ErrorInvalidWarcRecord = gotils.Error("INVALID_WARC_RECORD")
+
+const warcVersion = "WARC/1.1"
+
+const (
+	warcTypeInfo     = "warcinfo"
+	warcTypeRequest  = "request"
+	warcTypeResponse = "response"
+)
+
+type warcField struct {
+	Name  string
+	Value string
+}
+
+type warcHeader []warcField
+
+func (h warcHeader) Get(name string) string {
+	for _, field := range h {
+		if strings.EqualFold(field.Name, name) {
+			return field.Value
+		}
+	}
+
+	return ""
+}
+
+func (h *warcHeader) Set(name, value string) {
+	for i, field := range *h {
+		if strings.EqualFold(field.Name, name) {
+			(*h)[i] = warcField{Name: name, Value: value}
+			return
+		}
+	}
+
+	*h = append(*h, warcField{Name: name, Value: value})
+}
+
+type warcRecord struct {
+	Header warcHeader
+	Block  []byte
+}
+
+func newWarcRecord(warcType, targetUri, contentType string, block []byte) (*warcRecord, error) {
+	id, err := newWarcRecordId()
+	if err != nil {
+		return nil, err
+	}
+
+	header := warcHeader{}
+	header.Set("WARC-Type", warcType)
+	header.Set("WARC-Record-ID", id)
+	header.Set("WARC-Date", time.Now().UTC().Format(time.RFC3339))
+	if targetUri != "" {
+		header.Set("WARC-Target-URI", targetUri)
+	}
+	header.Set("Content-Type", contentType)
+	header.Set("WARC-Block-Digest", warcDigest(block))
+	return &warcRecord{Header: header, Block: block}, nil
+}
+
+func (r *warcRecord) WriteTo(w io.Writer) (int64, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteString(warcVersion + "\r\n")
+	r.Header.Set("Content-Length", strconv.Itoa(len(r.Block)))
+
+	for _, field := range r.Header {
+		fmt.Fprintf(buf, "%s: %s\r\n", field.Name, field.Value)
+	}
+
+	buf.WriteString("\r\n")
+	buf.Write(r.Block)
+	buf.WriteString("\r\n\r\n")
+	return buf.WriteTo(w)
+}
+
+func readWarcRecord(reader *bufio.Reader) (*warcRecord, error) {
+	protoReader := textproto.NewReader(reader)
+	version, err := protoReader.ReadLine()
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(version, "WARC/") {
+		return nil, ErrorInvalidWarcRecord
+	}
+
+	header := warcHeader{}
+	for {
+		line, err := protoReader.ReadContinuedLine()
+		if err != nil {
+			return nil, ErrorInvalidWarcRecord
+		}
+
+		if line == "" {
+			break
+		}
+
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			return nil, ErrorInvalidWarcRecord
+		}
+		header = append(header, warcField{Name: strings.TrimSpace(name), Value: strings.TrimSpace(value)})
+	}
+
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil || length < 0 {
+		return nil, ErrorInvalidWarcRecord
+	}
+
+	block := make([]byte, length)
+	if _, err := io.ReadFull(reader, block); err != nil {
+		return nil, ErrorInvalidWarcRecord
+	}
+
+	trailer := make([]byte, 4)
+	if _, err := io.ReadFull(reader, trailer); err != nil || string(trailer) != "\r\n\r\n" {
+		return nil, ErrorInvalidWarcRecord
+	}
+
+	return &warcRecord{Header: header, Block: block}, nil
+}
+
+func readWarcFile(path string) ([]*warcRecord, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+	reader := bufio.NewReader(file)
+
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		decompressor, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, err
+		}
+		defer decompressor.Close()
+		reader = bufio.NewReader(decompressor)
+	}
+
+	records := []*warcRecord{}
+	for {
+		if _, err := reader.Peek(1); err == io.EOF {
+			return records, nil
+		}
+
+		record, err := readWarcRecord(reader)
+		if err != nil {
+			return nil, err
+		}
+
+		records = append(records, record)
+	}
+}
+
+func newWarcRequestBlock(req *Request) ([]byte, error) {
+	parsedUrl, err := url.Parse(req.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "%s %s HTTP/1.1\r\n", req.method(), parsedUrl.RequestURI())
+	fmt.Fprintf(buf, "Host: %s\r\n", parsedUrl.Host)
+
+	header := req.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	if req.Referer != "" && header.Get("Referer") == "" {
+		header.Set("Referer", req.Referer)
+	}
+	header.Del("Host")
+	header.Set("Content-Length", strconv.Itoa(len(req.Body)))
+
+	if err := header.WriteSubset(buf, nil); err != nil {
+		return nil, err
+	}
+
+	buf.WriteString("\r\n")
+	buf.WriteString(req.Body)
+	return buf.Bytes(), nil
+}
+
+func newWarcResponseBlock(resp *Response) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, "HTTP/1.1 %d %s\r\n", resp.StatusCode, http.StatusText(resp.StatusCode))
+
+	header := resp.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	header.Del("Transfer-Encoding")
+	header.Del("Content-Encoding")
+	header.Set("Content-Length", strconv.Itoa(len(resp.Body)))
+
+	if err := header.WriteSubset(buf, nil); err != nil {
+		return nil, err
+	}
+
+	buf.WriteString("\r\n")
+	buf.WriteString(resp.Body)
+	return buf.Bytes(), nil
+}
+
+func parseWarcRequest(record *warcRecord) (*Request, error) {
+	httpReq, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(record.Block)))
+	if err != nil {
+		return nil, err
+	}
+
+	defer httpReq.Body.Close()
+	body, err := io.ReadAll(httpReq.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Request{
+		Method: httpReq.Method,
+		Url:    record.Header.Get("WARC-Target-URI"),
+		Header: httpReq.Header,
+		Body:   string(body),
+	}, nil
+}
+
+func parseWarcResponse(record *warcRecord) (*Response, error) {
+	httpResp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(record.Block)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	defer httpResp.Body.Close()
+	body, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Response{
+		Body:       string(body),
+		StatusCode: httpResp.StatusCode,
+		Header:     httpResp.Header,
+	}, nil
+}
+
+func newWarcRecordId() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+	return fmt.Sprintf("<urn:uuid:%x-%x-%x-%x-%x>", id[0:4], id[4:6], id[6:8], id[8:10], id[10:]), nil
+}
+
+func warcDigest(data []byte) string {
+	sum := sha1.Sum(data)
+	return "sha1:" + base32.StdEncoding.EncodeToString(sum[:])
+}
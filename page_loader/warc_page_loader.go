@@ -0,0 +1,83 @@
+package page_loader
+
+type warcRecordingPageLoader struct {
+	loader IPageLoader
+	writer IWarcWriter
+}
+
+func NewWarcRecordingPageLoader(loader IPageLoader, writer IWarcWriter) IPageLoader {
+	return &warcRecordingPageLoader{loader: loader, writer: writer}
+}
+
+func (l *warcRecordingPageLoader) LoadPage(req *Request) (*Response, error) {
+	resp, err := l.loader.LoadPage(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.writer.WriteExchange(req, resp); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+type warcReplayPageLoader struct {
+	responses map[string]*Response
+}
+
+func NewWarcReplayPageLoader(paths ...string) (IPageLoader, error) {
+	responses := map[string]*Response{}
+
+	for _, path := range paths {
+		records, err := readWarcFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		requests := map[string]*Request{}
+		for _, record := range records {
+			if record.Header.Get("WARC-Type") != warcTypeRequest {
+				continue
+			}
+
+			req, err := parseWarcRequest(record)
+			if err != nil {
+				return nil, err
+			}
+			requests[record.Header.Get("WARC-Concurrent-To")] = req
+		}
+
+		for _, record := range records {
+			if record.Header.Get("WARC-Type") != warcTypeResponse {
+				continue
+			}
+
+			resp, err := parseWarcResponse(record)
+			if err != nil {
+				return nil, err
+			}
+
+			req, ok := requests[record.Header.Get("WARC-Record-ID")]
+			if !ok {
+				req = NewRequest(record.Header.Get("WARC-Target-URI"))
+			}
+			responses[req.Fingerprint()] = resp
+		}
+	}
+
+	return &warcReplayPageLoader{responses: responses}, nil
+}
+
+func (l *warcReplayPageLoader) LoadPage(req *Request) (*Response, error) {
+	resp, ok := l.responses[req.Fingerprint()]
+	if !ok {
+		return nil, ErrorNotRecorded
+	}
+
+	return &Response{
+		Body:       resp.Body,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header.Clone(),
+	}, nil
+}
@@ -0,0 +1,109 @@
+package page_loader
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarcPageLoader(t *testing.T) {
+	warcDir := path.Join(os.Getenv("TMP_DIR"), "warc")
+	loader := &MockPageLoader{
+		LoadPage_: func(req *Request) (*Response, error) {
+			if req.Url == "http://demo.example/error" {
+				return nil, errors.New("UNEXPECTED_ERROR")
+			}
+
+			header := http.Header{}
+			header.Set("Content-Type", "text/html")
+			body := req.Method + " " + req.Url + " " + req.Body
+			return &Response{Body: body, StatusCode: http.StatusOK, Header: header}, nil
+		},
+	}
+
+	record := func(compress bool, requests ...*Request) []string {
+		gotils.NilOrPanic(os.Mkdir(warcDir, 0755))
+		writer := gotils.ResultOrPanic(NewWarcWriter(WarcWriterConfig{Dir: warcDir, MaxFileSize: 1, Compress: compress}))
+		recorder := NewWarcRecordingPageLoader(loader, writer)
+		for _, req := range requests {
+			gotils.ResultOrPanic(recorder.LoadPage(req))
+		}
+		gotils.NilOrPanic(writer.Close())
+		return gotils.ResultOrPanic(filepath.Glob(path.Join(warcDir, "*")))
+	}
+
+	for _, compress := range []bool{false, true} {
+		t.Run("Replays recorded responses", func(t *testing.T) {
+			defer os.RemoveAll(warcDir)
+			requests := []*Request{
+				NewRequest("http://demo.example/1"),
+				NewPostRequest("http://demo.example/search", "application/json", `{"page":2}`),
+			}
+			files := record(compress, requests...)
+
+			replayer, err := NewWarcReplayPageLoader(files...)
+
+			assert.Nil(t, err)
+			for _, req := range requests {
+				resp, err := replayer.LoadPage(req)
+				assert.Nil(t, err)
+				assert.Equal(t, req.Method+" "+req.Url+" "+req.Body, resp.Body)
+				assert.Equal(t, http.StatusOK, resp.StatusCode)
+				assert.Equal(t, "text/html", resp.Header.Get("Content-Type"))
+			}
+		})
+	}
+
+	t.Run("Replayer returns error for unknown requests", func(t *testing.T) {
+		defer os.RemoveAll(warcDir)
+		files := record(false, NewRequest("http://demo.example/1"))
+		replayer := gotils.ResultOrPanic(NewWarcReplayPageLoader(files...))
+
+		_, err := replayer.LoadPage(NewRequest("http://demo.example/2"))
+
+		assert.Equal(t, ErrorNotRecorded, err)
+	})
+
+	t.Run("Recorder returns error of the page loader", func(t *testing.T) {
+		defer os.RemoveAll(warcDir)
+		gotils.NilOrPanic(os.Mkdir(warcDir, 0755))
+		writer := gotils.ResultOrPanic(NewWarcWriter(WarcWriterConfig{Dir: warcDir}))
+		recorder := NewWarcRecordingPageLoader(loader, writer)
+
+		_, err := recorder.LoadPage(NewRequest("http://demo.example/error"))
+
+		assert.EqualError(t, err, "UNEXPECTED_ERROR")
+		assert.Nil(t, writer.Close())
+		assert.Empty(t, gotils.ResultOrPanic(filepath.Glob(path.Join(warcDir, "*"))))
+	})
+
+	t.Run("Recorder returns error if writing fails", func(t *testing.T) {
+		gotils.NilOrPanic(os.Mkdir(warcDir, 0755))
+		writer := gotils.ResultOrPanic(NewWarcWriter(WarcWriterConfig{Dir: warcDir}))
+		gotils.NilOrPanic(os.RemoveAll(warcDir))
+		recorder := NewWarcRecordingPageLoader(loader, writer)
+
+		_, err := recorder.LoadPage(NewRequest("http://demo.example/1"))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Returns error if WARC file is invalid", func(t *testing.T) {
+		defer os.RemoveAll(warcDir)
+		gotils.NilOrPanic(os.Mkdir(warcDir, 0755))
+		warcPath := path.Join(warcDir, "invalid.warc")
+		gotils.NilOrPanic(os.WriteFile(warcPath, []byte("invalid"), 0644))
+
+		_, missingErr := NewWarcReplayPageLoader(path.Join(warcDir, "missing.warc"))
+		_, invalidErr := NewWarcReplayPageLoader(warcPath)
+
+		assert.True(t, os.IsNotExist(missingErr))
+		assert.Equal(t, ErrorInvalidWarcRecord, invalidErr)
+	})
+}
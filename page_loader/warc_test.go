@@ -0,0 +1,116 @@
+package page_loader
+
+import (
+	"bufio"
+	"bytes"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarcRecord(t *testing.T) {
+	t.Run("Reads written record", func(t *testing.T) {
+		record := gotils.ResultOrPanic(newWarcRecord(warcTypeResponse, "http://demo.example/1", "text/plain", []byte("body\r\n\r\n")))
+		buf := &bytes.Buffer{}
+		gotils.ResultOrPanic(record.WriteTo(buf))
+
+		readRecord, err := readWarcRecord(bufio.NewReader(buf))
+
+		assert.Nil(t, err)
+		assert.Equal(t, record.Block, readRecord.Block)
+		assert.Equal(t, record.Header, readRecord.Header)
+		assert.Equal(t, "8", readRecord.Header.Get("Content-Length"))
+	})
+
+	t.Run("Writes WARC 1.1 record", func(t *testing.T) {
+		record := gotils.ResultOrPanic(newWarcRecord(warcTypeRequest, "http://demo.example/1", "text/plain", []byte("body")))
+		buf := &bytes.Buffer{}
+
+		gotils.ResultOrPanic(record.WriteTo(buf))
+
+		assert.True(t, strings.HasPrefix(buf.String(), "WARC/1.1\r\n"))
+		assert.True(t, strings.HasSuffix(buf.String(), "\r\n\r\nbody\r\n\r\n"))
+		assert.Regexp(t, regexp.MustCompile(`^<urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}>$`), record.Header.Get("WARC-Record-ID"))
+		assert.Equal(t, "sha1:AIED6RLZ4CFGCJBFYDA2C7XEPLOXQO4U", record.Header.Get("WARC-Block-Digest"))
+	})
+
+	t.Run("Writes field names as defined by the specification", func(t *testing.T) {
+		record := gotils.ResultOrPanic(newWarcRecord(warcTypeResponse, "http://demo.example/1", "application/http;msgtype=response", []byte("body")))
+		record.Header.Set("WARC-Concurrent-To", "<urn:uuid:1>")
+		record.Header.Set("WARC-Warcinfo-ID", "<urn:uuid:2>")
+		buf := &bytes.Buffer{}
+
+		gotils.ResultOrPanic(record.WriteTo(buf))
+
+		lines := strings.Split(strings.SplitN(buf.String(), "\r\n\r\n", 2)[0], "\r\n")
+		names := []string{}
+		for _, line := range lines[1:] {
+			names = append(names, strings.SplitN(line, ":", 2)[0])
+		}
+		assert.Equal(t, "WARC/1.1", lines[0])
+		assert.Equal(t, []string{
+			"WARC-Type",
+			"WARC-Record-ID",
+			"WARC-Date",
+			"WARC-Target-URI",
+			"Content-Type",
+			"WARC-Block-Digest",
+			"WARC-Concurrent-To",
+			"WARC-Warcinfo-ID",
+			"Content-Length",
+		}, names)
+		assert.Contains(t, buf.String(), "\r\nWARC-Target-URI: http://demo.example/1\r\n")
+	})
+
+	t.Run("Reads field names case-insensitively", func(t *testing.T) {
+		record := "WARC/1.1\r\nwarc-type: response\r\ncontent-length: 4\r\n\r\nbody\r\n\r\n"
+
+		readRecord, err := readWarcRecord(bufio.NewReader(strings.NewReader(record)))
+
+		assert.Nil(t, err)
+		assert.Equal(t, warcTypeResponse, readRecord.Header.Get("WARC-Type"))
+		assert.Equal(t, "body", string(readRecord.Block))
+	})
+
+	t.Run("Returns error for invalid record", func(t *testing.T) {
+		records := []string{
+			"HTTP/1.1 200 OK\r\n\r\n",
+			"WARC/1.1\r\nContent-Length: x\r\n\r\n",
+			"WARC/1.1\r\nContent-Length: 10\r\n\r\nbody",
+			"WARC/1.1\r\nContent-Length: 4\r\n\r\nbody",
+			"WARC/1.1\r\nContent-Length 4\r\n\r\nbody\r\n\r\n",
+		}
+
+		for _, record := range records {
+			_, err := readWarcRecord(bufio.NewReader(strings.NewReader(record)))
+
+			assert.Equal(t, ErrorInvalidWarcRecord, err)
+		}
+	})
+
+	t.Run("Converts request and response to HTTP messages", func(t *testing.T) {
+		req := NewPostRequest("http://demo.example/search?q=1", "application/json", `{"page":2}`)
+		req.Referer = "http://demo.example"
+		header := http.Header{}
+		header.Set("Content-Type", "text/html")
+		resp := &Response{Body: "<html></html>", StatusCode: http.StatusOK, Header: header}
+		requestBlock := gotils.ResultOrPanic(newWarcRequestBlock(req))
+		responseBlock := gotils.ResultOrPanic(newWarcResponseBlock(resp))
+
+		parsedReq, reqErr := parseWarcRequest(&warcRecord{Header: warcHeader{{Name: "WARC-Target-URI", Value: req.Url}}, Block: requestBlock})
+		parsedResp, respErr := parseWarcResponse(&warcRecord{Block: responseBlock})
+
+		assert.Nil(t, reqErr)
+		assert.Nil(t, respErr)
+		assert.True(t, strings.HasPrefix(string(requestBlock), "POST /search?q=1 HTTP/1.1\r\nHost: demo.example\r\n"))
+		assert.Equal(t, req.Fingerprint(), parsedReq.Fingerprint())
+		assert.Equal(t, "http://demo.example", parsedReq.Header.Get("Referer"))
+		assert.Equal(t, resp.Body, parsedResp.Body)
+		assert.Equal(t, resp.StatusCode, parsedResp.StatusCode)
+		assert.Equal(t, "text/html", parsedResp.Header.Get("Content-Type"))
+	})
+}
@@ -0,0 +1,185 @@
+package page_loader
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+type IWarcWriter interface {
+	WriteExchange(req *Request, resp *Response) error
+	Close() error
+}
+
+type WarcWriterConfig struct {
+	Dir         string
+	Prefix      string
+	MaxFileSize int64
+	Compress    bool
+}
+
+func (c *WarcWriterConfig) validate() {
+	if c.Prefix == "" {
+		c.Prefix = "grawler"
+	}
+
+	if c.MaxFileSize <= 0 {
+		c.MaxFileSize = 1 << 30
+	}
+}
+
+type warcWriter struct {
+	config   *WarcWriterConfig
+	mutex    *sync.Mutex
+	file     *os.File
+	infoId   string
+	size     int64
+	records  int
+	serial   int
+	openedAt string
+}
+
+func NewWarcWriter(config WarcWriterConfig) (IWarcWriter, error) {
+	config.validate()
+	if _, err := os.Stat(config.Dir); err != nil {
+		return nil, err
+	}
+
+	return &warcWriter{
+		config:   &config,
+		mutex:    &sync.Mutex{},
+		openedAt: time.Now().UTC().Format("20060102150405"),
+	}, nil
+}
+
+func (w *warcWriter) WriteExchange(req *Request, resp *Response) error {
+	responseBlock, err := newWarcResponseBlock(resp)
+	if err != nil {
+		return err
+	}
+
+	requestBlock, err := newWarcRequestBlock(req)
+	if err != nil {
+		return err
+	}
+
+	response, err := newWarcRecord(warcTypeResponse, req.Url, "application/http;msgtype=response", responseBlock)
+	if err != nil {
+		return err
+	}
+
+	response.Header.Set("WARC-Payload-Digest", warcDigest([]byte(resp.Body)))
+	request, err := newWarcRecord(warcTypeRequest, req.Url, "application/http;msgtype=request", requestBlock)
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("WARC-Concurrent-To", response.Header.Get("WARC-Record-ID"))
+
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file != nil && w.records > 0 && w.size >= w.config.MaxFileSize {
+		if err := w.closeFile(); err != nil {
+			return err
+		}
+	}
+
+	if w.file == nil {
+		if err := w.openFile(); err != nil {
+			return err
+		}
+	}
+
+	if err := w.writeRecords(request, response); err != nil {
+		return err
+	}
+
+	w.records++
+	return nil
+}
+
+func (w *warcWriter) Close() error {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	if w.file == nil {
+		return nil
+	}
+
+	return w.closeFile()
+}
+
+func (w *warcWriter) openFile() error {
+	extension := ".warc"
+	if w.config.Compress {
+		extension += ".gz"
+	}
+
+	name := fmt.Sprintf("%s-%s-%05d%s", w.config.Prefix, w.openedAt, w.serial, extension)
+	info, err := newWarcRecord(warcTypeInfo, "", "application/warc-fields", []byte("software: grawler\r\nformat: WARC File Format 1.1\r\n"))
+	if err != nil {
+		return err
+	}
+
+	info.Header.Set("WARC-Filename", name)
+	file, err := os.OpenFile(filepath.Join(w.config.Dir, name), os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	w.file = file
+	w.size = 0
+	w.records = 0
+	w.serial++
+	w.infoId = info.Header.Get("WARC-Record-ID")
+
+	if err := w.writeRecords(info); err != nil {
+		file.Close()
+		w.file = nil
+		return err
+	}
+
+	return nil
+}
+
+func (w *warcWriter) closeFile() error {
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
+
+func (w *warcWriter) writeRecords(records ...*warcRecord) error {
+	buf := &bytes.Buffer{}
+	for _, record := range records {
+		if w.infoId != "" && record.Header.Get("WARC-Type") != warcTypeInfo {
+			record.Header.Set("WARC-Warcinfo-ID", w.infoId)
+		}
+
+		if err := w.encodeRecord(buf, record); err != nil {
+			return err
+		}
+	}
+
+	n, err := buf.WriteTo(w.file)
+	w.size += n
+	return err
+}
+
+func (w *warcWriter) encodeRecord(buf *bytes.Buffer, record *warcRecord) error {
+	if !w.config.Compress {
+		_, err := record.WriteTo(buf)
+		return err
+	}
+
+	compressor := gzip.NewWriter(buf)
+	if _, err := record.WriteTo(compressor); err != nil {
+		return err
+	}
+
+	return compressor.Close()
+}
@@ -0,0 +1,107 @@
+package page_loader
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DAtek/gotils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWarcWriter(t *testing.T) {
+	warcDir := path.Join(os.Getenv("TMP_DIR"), "warc")
+	createWarcDir := func() {
+		gotils.NilOrPanic(os.Mkdir(warcDir, 0755))
+	}
+	deleteWarcDir := func() {
+		gotils.NilOrPanic(os.RemoveAll(warcDir))
+	}
+	listWarcFiles := func() []string {
+		return gotils.ResultOrPanic(filepath.Glob(path.Join(warcDir, "*")))
+	}
+	resp := &Response{Body: "<html></html>", StatusCode: http.StatusOK, Header: http.Header{}}
+
+	t.Run("Writes warcinfo, request and response records", func(t *testing.T) {
+		createWarcDir()
+		defer deleteWarcDir()
+		writer := gotils.ResultOrPanic(NewWarcWriter(WarcWriterConfig{Dir: warcDir}))
+
+		gotils.NilOrPanic(writer.WriteExchange(NewRequest("http://demo.example/1"), resp))
+		gotils.NilOrPanic(writer.Close())
+
+		files := listWarcFiles()
+		assert.Equal(t, 1, len(files))
+		assert.True(t, strings.HasSuffix(files[0], "-00000.warc"))
+		records := gotils.ResultOrPanic(readWarcFile(files[0]))
+		assert.Equal(t, 3, len(records))
+		info, request, response := records[0], records[1], records[2]
+		assert.Equal(t, warcTypeInfo, info.Header.Get("WARC-Type"))
+		assert.Equal(t, path.Base(files[0]), info.Header.Get("WARC-Filename"))
+		assert.Equal(t, warcTypeRequest, request.Header.Get("WARC-Type"))
+		assert.Equal(t, warcTypeResponse, response.Header.Get("WARC-Type"))
+		assert.Equal(t, "http://demo.example/1", response.Header.Get("WARC-Target-URI"))
+		assert.Equal(t, response.Header.Get("WARC-Record-ID"), request.Header.Get("WARC-Concurrent-To"))
+		assert.Equal(t, info.Header.Get("WARC-Record-ID"), response.Header.Get("WARC-Warcinfo-ID"))
+		assert.Equal(t, warcDigest([]byte(resp.Body)), response.Header.Get("WARC-Payload-Digest"))
+		assert.Equal(t, warcDigest(response.Block), response.Header.Get("WARC-Block-Digest"))
+
+		content := string(gotils.ResultOrPanic(os.ReadFile(files[0])))
+		for _, name := range []string{"WARC-Type", "WARC-Record-ID", "WARC-Target-URI", "WARC-Block-Digest", "WARC-Concurrent-To", "WARC-Warcinfo-ID", "WARC-Payload-Digest"} {
+			assert.Contains(t, content, "\r\n"+name+": ")
+		}
+	})
+
+	t.Run("Rotates files by size", func(t *testing.T) {
+		createWarcDir()
+		defer deleteWarcDir()
+		writer := gotils.ResultOrPanic(NewWarcWriter(WarcWriterConfig{Dir: warcDir, Prefix: "crawl", MaxFileSize: 1}))
+
+		for _, u := range []string{"http://demo.example/1", "http://demo.example/2", "http://demo.example/3"} {
+			gotils.NilOrPanic(writer.WriteExchange(NewRequest(u), resp))
+		}
+		gotils.NilOrPanic(writer.Close())
+
+		files := listWarcFiles()
+		assert.Equal(t, 3, len(files))
+		for _, file := range files {
+			assert.True(t, strings.HasPrefix(path.Base(file), "crawl-"))
+			assert.Equal(t, 3, len(gotils.ResultOrPanic(readWarcFile(file))))
+		}
+	})
+
+	t.Run("Writes compressed records", func(t *testing.T) {
+		createWarcDir()
+		defer deleteWarcDir()
+		writer := gotils.ResultOrPanic(NewWarcWriter(WarcWriterConfig{Dir: warcDir, Compress: true}))
+
+		gotils.NilOrPanic(writer.WriteExchange(NewRequest("http://demo.example/1"), resp))
+		gotils.NilOrPanic(writer.WriteExchange(NewRequest("http://demo.example/2"), resp))
+		gotils.NilOrPanic(writer.Close())
+
+		files := listWarcFiles()
+		assert.Equal(t, 1, len(files))
+		assert.True(t, strings.HasSuffix(files[0], ".warc.gz"))
+		assert.Equal(t, 5, len(gotils.ResultOrPanic(readWarcFile(files[0]))))
+	})
+
+	t.Run("Returns error if directory not exists", func(t *testing.T) {
+		_, err := NewWarcWriter(WarcWriterConfig{Dir: "/var/this_directory_does_not_exists"})
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Returns error for invalid URL", func(t *testing.T) {
+		createWarcDir()
+		defer deleteWarcDir()
+		writer := gotils.ResultOrPanic(NewWarcWriter(WarcWriterConfig{Dir: warcDir}))
+
+		err := writer.WriteExchange(NewRequest("http://demo.example/%zz"), resp)
+
+		assert.Error(t, err)
+		assert.Nil(t, writer.Close())
+	})
+}
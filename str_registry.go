@@ -14,8 +14,8 @@ func newStringRegistry() *stringRegistry {
 	}
 }
 
-func (c *stringRegistry) add(item string) {
-	c.analyzedUrls.Add(item)
+func (c *stringRegistry) add(item string) bool {
+	return c.analyzedUrls.Add(item)
 }
 
 func (c *stringRegistry) getNew(items []string) []string {
@@ -16,4 +16,11 @@ func TestStrRegistry(t *testing.T) {
 
 		assert.Equal(t, []string{"b"}, newItems)
 	})
+
+	t.Run("Reports if item is new", func(t *testing.T) {
+		r := newStringRegistry()
+
+		assert.True(t, r.add("a"))
+		assert.False(t, r.add("a"))
+	})
 }